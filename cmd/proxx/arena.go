@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/arena"
+	"proxx/internal/proxx/game"
+	"slices"
+	"strings"
+	"time"
+)
+
+// runArena implements the "proxx arena [flags] <bot>... [-- game flags]" command: every bot plays the same
+// seeded games and the results are compared. A bot is a command line, e.g. "python3 bot.py".
+func runArena(args []string) error {
+	fs := flag.NewFlagSet("arena", flag.ContinueOnError)
+	games := fs.Int("games", 10, "number of games every bot plays")
+	seed := fs.Int64("seed", 1, "seed of the first game, the next games use the following seeds")
+	moveTime := fs.Duration("move-time", time.Second, "time a bot may think about a move")
+	botStderr := fs.Bool("bot-stderr", false, "show the standard error of the bots")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	bots, gameArgs := fs.Args(), []string(nil)
+	if i := slices.Index(bots, "--"); i >= 0 {
+		bots, gameArgs = bots[:i], bots[i+1:]
+	}
+
+	if len(bots) == 0 {
+		return errors.New("usage: proxx arena [-games N] [-seed N] [-move-time D] [-bot-stderr] <bot>... [-- game flags]")
+	}
+
+	if *games <= 0 {
+		return errors.New("games should be positive")
+	}
+
+	if *moveTime <= 0 {
+		return errors.New("move-time should be positive")
+	}
+
+	opts, err := input.ParseFlags(gameArgs)
+	if err != nil {
+		return err
+	}
+
+	if !opts.DescribeBoard() {
+		opts.Preset = "beginner"
+	}
+
+	gameCfg, err := opts.GameConfig(nil)
+	if err != nil {
+		return err
+	}
+
+	var stderr io.Writer
+	if *botStderr {
+		stderr = os.Stderr
+	}
+
+	fmt.Printf("%d games on a %dx%d board with %d black holes, seeds from %d.\n\n",
+		*games, gameCfg.NumRows, gameCfg.NumCols, gameCfg.BlackHoleCount(), *seed)
+
+	for _, bot := range bots {
+		report, err := arena.Run(arena.Config{
+			Command: strings.Fields(bot),
+			Games:   *games,
+			Seed:    *seed,
+			GameConfig: func(seed int64) (game.Config, error) {
+				locator, err := game.NewBlackHoleLocator(opts.Locator, seed)
+				if err != nil {
+					return game.Config{}, err
+				}
+
+				cfg := gameCfg
+				cfg.BlackHoleLocator = locator
+
+				return cfg, nil
+			},
+			MoveTime: *moveTime,
+			Stderr:   stderr,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", bot, err)
+		}
+
+		showArenaReport(os.Stdout, report)
+	}
+
+	return nil
+}
+
+// showArenaReport prints the results of a bot and the reasons of its failures.
+func showArenaReport(w io.Writer, r arena.Report) {
+	fmt.Fprintf(w, "%s\n", r.Bot)
+	fmt.Fprintf(w, "  win rate: %.1f%%, average progress: %.1f%%\n", r.WinRate()*100, r.AverageProgress()*100)
+
+	outcomes := make([]string, 0, len(arena.Outcomes))
+	for _, o := range arena.Outcomes {
+		outcomes = append(outcomes, fmt.Sprintf("%s %d", o, r.Count(o)))
+	}
+
+	fmt.Fprintf(w, "  games: %s\n", strings.Join(outcomes, ", "))
+	fmt.Fprintf(w, "  time: %s, %s per move\n", r.Time().Round(time.Millisecond), r.AverageMoveTime().Round(time.Microsecond))
+
+	for _, g := range r.Games {
+		if g.Err != nil {
+			fmt.Fprintf(w, "  seed %d: %s: %s\n", g.Seed, g.Outcome, g.Err)
+		}
+	}
+
+	fmt.Fprintln(w)
+}
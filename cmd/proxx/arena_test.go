@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"proxx/internal/proxx/arena"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunArena_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		"no bots":            {"-games", "3"},
+		"only game flags":    {"--", "-preset", "expert"},
+		"no games":           {"-games", "0", "./bot"},
+		"no move time":       {"-move-time", "0s", "./bot"},
+		"invalid game flags": {"./bot", "--", "-preset", "nope"},
+	}
+
+	for name, args := range tests {
+		args := args
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Error(t, runArena(args))
+		})
+	}
+}
+
+func TestShowArenaReport(t *testing.T) {
+	t.Parallel()
+
+	r := arena.Report{
+		Bot: "lucky",
+		Games: []arena.GameResult{
+			{Seed: 1, Outcome: arena.OutcomeWon, Moves: 3, Progress: 1, Time: 30 * time.Millisecond},
+			{Seed: 2, Outcome: arena.OutcomeTimeout, Moves: 1, Progress: 0.5, Time: time.Second,
+				Err: arena.ErrBotTimeout},
+			{Seed: 3, Outcome: arena.OutcomeIllegal, Err: fmt.Errorf("%w: %q isn't a move", arena.ErrIllegalMove, "hi")},
+			{Seed: 4, Outcome: arena.OutcomeLost, Moves: 4, Progress: 0.3},
+		},
+	}
+
+	var out bytes.Buffer
+
+	showArenaReport(&out, r)
+
+	assert.Equal(t, `lucky
+  win rate: 25.0%, average progress: 45.0%
+  games: won 1, lost 1, timeout 1, crash 0, illegal 1
+  time: 1.03s, 128.75ms per move
+  seed 2: timeout: the bot didn't answer in time
+  seed 3: illegal: illegal move: "hi" isn't a move
+
+`, out.String())
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"proxx/internal/proxx/engine"
+)
+
+// runEngine implements the "proxx engine [flags]" command: a front end plays games through JSON-RPC 2.0
+// over the standard input and output.
+func runEngine(args []string) error {
+	fs := flag.NewFlagSet("engine", flag.ContinueOnError)
+	presetsFile := fs.String("presets", "", "JSON file with user-defined presets")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	custom, err := readPresets(*presetsFile)
+	if err != nil {
+		return err
+	}
+
+	return engine.New(engine.Config{CustomPresets: custom}).Serve(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// engineProcessEnv makes the test binary run the engine instead of the tests, so the tests can drive
+// the engine as a separate process the way a front end does.
+const engineProcessEnv = "PROXX_TEST_ENGINE_PROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(engineProcessEnv) == "1" {
+		if err := runEngine(nil); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// rpcMessage is any message written by the engine: a response or a notification.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Reason string `json:"reason"`
+		} `json:"data"`
+	} `json:"error"`
+}
+
+type engineEvent struct {
+	Type  string `json:"type"`
+	Cells []struct {
+		Row   int    `json:"row"`
+		Col   int    `json:"col"`
+		Value string `json:"value"`
+	} `json:"cells"`
+	Exploded *[2]int `json:"exploded"`
+}
+
+type engineState struct {
+	Rows       int        `json:"rows"`
+	Cols       int        `json:"cols"`
+	BlackHoles int        `json:"black_holes"`
+	Flags      int        `json:"flags"`
+	Status     string     `json:"status"`
+	Cells      [][]string `json:"cells"`
+}
+
+// engineProcess is the engine running as a child process.
+type engineProcess struct {
+	t      *testing.T
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	nextID int
+}
+
+func startEngine(t *testing.T) *engineProcess {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), engineProcessEnv+"=1")
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	require.NoError(t, err)
+
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+
+	require.NoError(t, cmd.Start())
+
+	t.Cleanup(func() {
+		stdin.Close()
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err, "the engine should exit when its input ends")
+		case <-time.After(10 * time.Second):
+			_ = cmd.Process.Kill()
+			t.Error("the engine doesn't exit when its input ends")
+		}
+	})
+
+	return &engineProcess{t: t, stdin: stdin, stdout: bufio.NewReader(stdout)}
+}
+
+func (e *engineProcess) send(line string) {
+	e.t.Helper()
+
+	_, err := io.WriteString(e.stdin, line+"\n")
+	require.NoError(e.t, err)
+}
+
+func (e *engineProcess) read() rpcMessage {
+	e.t.Helper()
+
+	line, err := e.stdout.ReadBytes('\n')
+	require.NoError(e.t, err)
+
+	var m rpcMessage
+	require.NoError(e.t, json.Unmarshal(line, &m), string(line))
+	require.Equal(e.t, "2.0", m.JSONRPC)
+
+	return m
+}
+
+// call sends the request and returns its response with the events pushed before it.
+func (e *engineProcess) call(method string, params any) (rpcMessage, []engineEvent) {
+	e.t.Helper()
+
+	e.nextID++
+
+	req := map[string]any{"jsonrpc": "2.0", "id": e.nextID, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+
+	data, err := json.Marshal(req)
+	require.NoError(e.t, err)
+
+	e.send(string(data))
+
+	var events []engineEvent
+
+	for {
+		m := e.read()
+
+		if m.Method == "event" {
+			var ev engineEvent
+			require.NoError(e.t, json.Unmarshal(m.Params, &ev))
+
+			events = append(events, ev)
+
+			continue
+		}
+
+		require.JSONEq(e.t, fmt.Sprint(e.nextID), string(m.ID))
+
+		return m, events
+	}
+}
+
+// result calls the method, checks it succeeds and decodes the result.
+func (e *engineProcess) result(method string, params any, out any) []engineEvent {
+	e.t.Helper()
+
+	m, events := e.call(method, params)
+	require.Nil(e.t, m.Error, "%s failed", method)
+
+	if out != nil {
+		require.NoError(e.t, json.Unmarshal(m.Result, out))
+	}
+
+	return events
+}
+
+// expectError calls the method and checks it fails with the code and the reason.
+func (e *engineProcess) expectError(method string, params any, code int, reason string) {
+	e.t.Helper()
+
+	m, _ := e.call(method, params)
+	require.NotNil(e.t, m.Error, "%s should fail", method)
+	assert.Equal(e.t, code, m.Error.Code)
+	assert.Equal(e.t, reason, m.Error.Data.Reason)
+	assert.NotEmpty(e.t, m.Error.Message)
+}
+
+func eventTypes(events []engineEvent) []string {
+	var types []string
+
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+
+	return types
+}
+
+var engineNewParams = map[string]any{"rows": 4, "cols": 4, "holes": 2, "seed": testSeed}
+
+func TestEngine_Game(t *testing.T) {
+	e := startEngine(t)
+
+	var state engineState
+
+	assert.Empty(t, e.result("new", engineNewParams, &state))
+	assert.Equal(t, engineState{Rows: 4, Cols: 4, BlackHoles: 2, Status: "in progress",
+		Cells: [][]string{{"?", "?", "?", "?"}, {"?", "?", "?", "?"}, {"?", "?", "?", "?"}, {"?", "?", "?", "?"}}}, state)
+
+	hole := testLayout(t)[0]
+
+	events := e.result("flag", map[string]int{"row": hole.Row, "col": hole.Col}, &state)
+	assert.Equal(t, []string{"game_started", "cell_flagged"}, eventTypes(events))
+	assert.Equal(t, "F", events[1].Cells[0].Value)
+	assert.Equal(t, 1, state.Flags)
+
+	events = e.result("undo", nil, &state)
+	assert.Equal(t, []string{"move_undone"}, eventTypes(events))
+	assert.Equal(t, 0, state.Flags)
+
+	e.result("redo", nil, &state)
+	assert.Equal(t, 1, state.Flags)
+
+	var hint struct {
+		Row     int    `json:"row"`
+		Col     int    `json:"col"`
+		Certain bool   `json:"certain"`
+		Reason  string `json:"reason"`
+	}
+
+	e.result("hint", nil, &hint)
+	assert.NotEmpty(t, hint.Reason)
+
+	// open the safe cells until the game is won
+	for state.Status == "in progress" {
+		var result struct {
+			Revealed []struct {
+				Row int `json:"row"`
+				Col int `json:"col"`
+			} `json:"revealed"`
+			State engineState `json:"state"`
+		}
+
+		row, col := -1, -1
+
+		for i := 0; i < 4 && row < 0; i++ {
+			for j := 0; j < 4; j++ {
+				if state.Cells[i][j] == "?" && !isBlackHole(testLayout(t), i, j) {
+					row, col = i, j
+					break
+				}
+			}
+		}
+
+		events = e.result("open", map[string]int{"row": row, "col": col}, &result)
+		require.NotEmpty(t, result.Revealed)
+		assert.Equal(t, "cells_opened", events[0].Type)
+		assert.Len(t, events[0].Cells, len(result.Revealed))
+
+		state = result.State
+	}
+
+	assert.Equal(t, "won", state.Status)
+	assert.Equal(t, "game_won", events[len(events)-1].Type)
+
+	e.expectError("open", map[string]int{"row": 0, "col": 0}, 6, "game_over")
+	e.expectError("hint", nil, 6, "game_over")
+}
+
+func TestEngine_SaveAndLoad(t *testing.T) {
+	e := startEngine(t)
+
+	hole := testLayout(t)[0]
+
+	e.result("new", engineNewParams, nil)
+
+	var before engineState
+	e.result("flag", map[string]int{"row": hole.Row, "col": hole.Col}, &before)
+
+	var saved struct {
+		Game json.RawMessage `json:"game"`
+		Path string          `json:"path"`
+	}
+
+	e.result("save", nil, &saved)
+	require.NotEmpty(t, saved.Game)
+
+	path := filepath.Join(t.TempDir(), "game.json")
+	e.result("save", map[string]string{"path": path}, &saved)
+	assert.Equal(t, path, saved.Path)
+
+	e.result("new", map[string]any{"preset": "expert"}, nil)
+
+	var loaded engineState
+
+	e.result("load", map[string]string{"path": path}, &loaded)
+	assert.Equal(t, before, loaded)
+
+	// the loaded game is played on
+	events := e.result("flag", map[string]int{"row": hole.Row, "col": hole.Col}, &loaded)
+	assert.Equal(t, []string{"cell_flagged"}, eventTypes(events))
+	assert.Equal(t, 0, loaded.Flags)
+
+	e.result("new", engineNewParams, nil)
+	e.result("load", map[string]json.RawMessage{"game": saved.Game}, &loaded)
+	assert.Equal(t, before, loaded)
+
+	e.expectError("load", map[string]string{"path": filepath.Join(t.TempDir(), "missing.json")}, 13, "file_error")
+	e.expectError("load", map[string]any{"game": map[string]int{"version": 99}}, 14, "invalid_save")
+	e.expectError("load", map[string]any{"game": "nope"}, 14, "invalid_save")
+	e.expectError("load", nil, -32602, "invalid_params")
+}
+
+func TestEngine_Errors(t *testing.T) {
+	e := startEngine(t)
+
+	e.expectError("state", nil, 1, "no_game")
+	e.expectError("teleport", nil, -32601, "method_not_found")
+	e.expectError("new", map[string]string{"preset": "impossible"}, 2, "unknown_preset")
+	e.expectError("new", map[string]any{"preset": "beginner", "locator": "magic"}, 3, "unknown_locator")
+	e.expectError("new", map[string]int{"rows": 2, "cols": 2, "holes": 4}, 4, "invalid_config")
+	e.expectError("new", map[string]int{"rows": 1000, "cols": 1000, "holes": 10}, 4, "invalid_config")
+	e.expectError("new", map[string]any{"preset": "beginner", "rows": 9}, -32602, "invalid_params")
+	e.expectError("new", map[string]int{"lives": 3}, -32602, "invalid_params")
+
+	e.result("new", engineNewParams, nil)
+
+	e.expectError("open", map[string]int{"row": 0}, -32602, "invalid_params")
+	e.expectError("open", map[string]int{"row": 4, "col": 0}, 5, "position_outside_board")
+	e.expectError("chord", map[string]int{"row": 0, "col": 0}, 9, "cell_not_open")
+	e.expectError("undo", nil, 11, "nothing_to_undo")
+	e.expectError("redo", nil, 12, "nothing_to_redo")
+
+	hole := testLayout(t)[0]
+	e.result("flag", map[string]int{"row": hole.Row, "col": hole.Col}, nil)
+	e.expectError("open", map[string]int{"row": hole.Row, "col": hole.Col}, 8, "cell_flagged")
+}
+
+func TestEngine_Protocol(t *testing.T) {
+	e := startEngine(t)
+
+	testCases := []struct {
+		name    string
+		request string
+		code    int
+	}{
+		{name: "Parse error", request: `{"jsonrpc": "2.0", "method": `, code: -32700},
+		{name: "Not an object", request: `"state"`, code: -32600},
+		{name: "Wrong version", request: `{"jsonrpc": "1.0", "id": 1, "method": "state"}`, code: -32600},
+		{name: "Method isn't a string", request: `{"jsonrpc": "2.0", "id": 1, "method": 1}`, code: -32600},
+		{name: "Empty batch", request: `[]`, code: -32600},
+	}
+
+	for _, tc := range testCases {
+		e.send(tc.request)
+
+		m := e.read()
+		require.NotNil(t, m.Error, tc.name)
+		assert.Equal(t, tc.code, m.Error.Code, tc.name)
+		assert.Equal(t, "null", string(m.ID), tc.name)
+	}
+
+	// notifications get no response, even if they fail
+	e.send(`{"jsonrpc": "2.0", "method": "state"}`)
+	e.send(`{"jsonrpc": "2.0", "method": "new", "params": {"preset": "beginner", "seed": 7}}`)
+	e.send(`{"jsonrpc": "2.0", "id": "last", "method": "state"}`)
+
+	m := e.read()
+	assert.Equal(t, `"last"`, string(m.ID))
+	assert.Nil(t, m.Error, "the game is started by the notification")
+
+	// a batch gets an array of responses in the same order, the events go first
+	e.send(`[{"jsonrpc": "2.0", "id": 1, "method": "flag", "params": {"row": 0, "col": 0}},` +
+		`{"jsonrpc": "2.0", "method": "state"}, 42, {"jsonrpc": "2.0", "id": 2, "method": "state"}]`)
+
+	assert.Equal(t, "event", e.read().Method)
+	assert.Equal(t, "event", e.read().Method)
+
+	line, err := e.stdout.ReadBytes('\n')
+	require.NoError(t, err)
+
+	var batch []rpcMessage
+	require.NoError(t, json.Unmarshal(line, &batch))
+	require.Len(t, batch, 3)
+	assert.Equal(t, "1", string(batch[0].ID))
+	assert.Equal(t, -32600, batch[1].Error.Code)
+	assert.Equal(t, "2", string(batch[2].ID))
+
+	// a batch of notifications gets nothing
+	e.send(`[{"jsonrpc": "2.0", "method": "state"}]`)
+	e.send(`{"jsonrpc": "2.0", "id": 3, "method": "state"}`)
+	assert.Equal(t, "3", string(e.read().ID))
+}
@@ -0,0 +1,279 @@
+package input
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"strings"
+	"time"
+)
+
+var (
+	ErrIncompleteBoardFlags = errors.New("-rows, -cols and -holes (or -density) should be provided together")
+	ErrPresetWithBoardFlags = errors.New("-preset can't be combined with -rows, -cols, -holes and -density")
+	ErrInvalidColorMode     = errors.New("-color should be auto, always or never")
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// Options represents the game options passed via command-line flags.
+type Options struct {
+	Rows           int
+	Cols           int
+	Holes          int
+	Density        float64
+	Seed           int64
+	Preset         string
+	PresetsFile    string
+	Locator        string
+	FirstClickSafe bool
+	ZeroBased      bool
+	Theme          string
+	Color          string
+	Format         string
+
+	// Presets holds the built-in presets followed by the ones defined in PresetsFile.
+	Presets []game.Preset
+
+	customPresets []game.Preset
+	boardFlags    int
+	seedSet       bool
+}
+
+// ParseFlags parses the command-line flags of the game.
+// If the flags describe the board, the resulting game configuration is validated as well.
+func ParseFlags(args []string) (Options, error) {
+	var (
+		o          Options
+		holesSet   bool
+		densitySet bool
+		presetsSet bool
+	)
+
+	fs := flag.NewFlagSet("proxx", flag.ContinueOnError)
+	fs.IntVar(&o.Rows, "rows", 0, "number of rows")
+	fs.IntVar(&o.Cols, "cols", 0, "number of columns")
+	fs.IntVar(&o.Holes, "holes", 0, "number of black holes")
+	fs.Float64Var(&o.Density, "density", 0, "percentage of cells occupied by black holes, an alternative to -holes")
+	fs.Int64Var(&o.Seed, "seed", 0, "seed of the black hole locator, the same seed gives the same board")
+	fs.StringVar(&o.Preset, "preset", "", "board preset: beginner, intermediate, expert or a user-defined one")
+	fs.StringVar(&o.PresetsFile, "presets", defaultPresetsFile(), "JSON file with user-defined presets")
+	fs.StringVar(&o.Locator, "locator", game.UniformLocatorName, "black hole locator")
+	fs.BoolVar(&o.FirstClickSafe, "first-click-safe", false, "never lose on the first click")
+	fs.BoolVar(&o.ZeroBased, "zero-based", false, "number rows and columns from 0")
+	fs.StringVar(&o.Theme, "theme", render.ASCII.Name, "look of the board: ascii, unicode or emoji")
+	fs.StringVar(&o.Color, "color", ColorAuto, "colors: auto (only on a terminal without NO_COLOR set), always or never")
+	fs.StringVar(&o.Format, "format", render.FormatText, "format of the board: text, json, markdown, csv or html")
+
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "rows", "cols":
+			o.boardFlags++
+		case "holes":
+			o.boardFlags++
+			holesSet = true
+		case "density":
+			o.boardFlags++
+			densitySet = true
+		case "seed":
+			o.seedSet = true
+		case "presets":
+			presetsSet = true
+		}
+	})
+
+	if holesSet && densitySet {
+		return Options{}, game.ErrBlackHoleCountAndDensity
+	}
+
+	if o.boardFlags > 0 && o.boardFlags < 3 {
+		return Options{}, ErrIncompleteBoardFlags
+	}
+
+	if o.boardFlags > 0 && o.Preset != "" {
+		return Options{}, ErrPresetWithBoardFlags
+	}
+
+	if _, err := render.ThemeByName(o.Theme); err != nil {
+		return Options{}, err
+	}
+
+	if o.Color != ColorAuto && o.Color != ColorAlways && o.Color != ColorNever {
+		return Options{}, fmt.Errorf("%w: %q", ErrInvalidColorMode, o.Color)
+	}
+
+	o.Format = strings.ToLower(o.Format)
+
+	if _, err := render.NewRenderer(o.Format, o.Notation()); err != nil {
+		return Options{}, err
+	}
+
+	custom, err := readPresetsFile(o.PresetsFile, presetsSet)
+	if err != nil {
+		return Options{}, err
+	}
+
+	o.Presets = game.Presets(custom...)
+	o.customPresets = custom
+
+	if o.Preset != "" {
+		if _, err := game.PresetByName(o.Preset, custom...); err != nil {
+			return Options{}, err
+		}
+	}
+
+	if _, err := game.NewBlackHoleLocator(o.Locator, o.Seed); err != nil {
+		return Options{}, err
+	}
+
+	if o.DescribeBoard() {
+		if _, err := o.GameConfig(nil); err != nil {
+			return Options{}, err
+		}
+	}
+
+	return o, nil
+}
+
+// defaultPresetsFile returns the path to the user-defined presets in the user's configuration directory.
+func defaultPresetsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "proxx", "presets.json")
+}
+
+// readPresetsFile reads user-defined presets from the file.
+// A missing file is fine unless it was explicitly requested.
+func readPresetsFile(path string, required bool) ([]game.Preset, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	custom, err := game.ReadPresets(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return custom, nil
+}
+
+// Notation returns the notation of cell positions chosen by the options.
+func (o Options) Notation() command.Notation {
+	return command.Notation{ZeroBased: o.ZeroBased}
+}
+
+// Renderer returns the renderer of boards written to w in the format chosen by the options.
+// An unknown format falls back to text.
+func (o Options) Renderer(w io.Writer) render.Renderer {
+	if o.Format == render.FormatText {
+		return o.Terminal(w)
+	}
+
+	r, err := render.NewRenderer(o.Format, o.Notation())
+	if err != nil {
+		return o.Terminal(w)
+	}
+
+	return r
+}
+
+// Terminal returns the renderer of boards written to w as text chosen by the options.
+// An unknown theme falls back to ASCII.
+func (o Options) Terminal(w io.Writer) render.Terminal {
+	theme, err := render.ThemeByName(o.Theme)
+	if err != nil {
+		theme = render.ASCII
+	}
+
+	r := render.NewTerminal(w, theme, o.Notation())
+
+	switch o.Color {
+	case ColorAlways:
+		r.Color = true
+	case ColorNever:
+		r.Color = false
+	}
+
+	return r
+}
+
+// DescribeBoard returns true if the board is described by the flags and there is no need to ask for it.
+func (o Options) DescribeBoard() bool {
+	return o.boardFlags > 0 || o.Preset != ""
+}
+
+// GameConfig returns the game configuration described by the options.
+// If the options don't describe the board, the player is asked by the prompter to choose a preset or to describe it.
+// The configuration is validated the same way a new game validates it.
+func (o Options) GameConfig(p *Prompter) (game.Config, error) {
+	var cfg game.Config
+
+	switch {
+	case o.Preset != "":
+		preset, err := game.PresetByName(o.Preset, o.customPresets...)
+		if err != nil {
+			return game.Config{}, err
+		}
+
+		cfg = preset.Config(nil)
+	case o.boardFlags > 0:
+		cfg = game.Config{NumRows: o.Rows, NumCols: o.Cols, NumBlackHoles: o.Holes, BlackHoleDensity: o.Density}
+	default:
+		var err error
+
+		presets := o.Presets
+		if presets == nil {
+			presets = game.Presets()
+		}
+
+		if cfg, err = p.GetGameConfig(presets); err != nil {
+			return game.Config{}, err
+		}
+	}
+
+	seed := o.Seed
+	if !o.seedSet {
+		seed = time.Now().UnixNano()
+	}
+
+	locator, err := game.NewBlackHoleLocator(o.Locator, seed)
+	if err != nil {
+		return game.Config{}, err
+	}
+
+	cfg.BlackHoleLocator = locator
+	cfg.FirstClickSafe = o.FirstClickSafe
+
+	if err := cfg.Validate(); err != nil {
+		return game.Config{}, err
+	}
+
+	return cfg, nil
+}
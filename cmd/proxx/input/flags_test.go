@@ -0,0 +1,119 @@
+package input_test
+
+import (
+	"os"
+	"path/filepath"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{name: "Board flags", args: []string{"-rows", "5", "-cols", "6", "-holes", "7"}},
+		{name: "Preset", args: []string{"-preset", "expert", "-first-click-safe"}},
+		{name: "Missing board flag", args: []string{"-rows", "5", "-cols", "6"}, err: input.ErrIncompleteBoardFlags},
+		{name: "Preset with board flags", args: []string{"-preset", "expert", "-rows", "5", "-cols", "6", "-holes", "7"},
+			err: input.ErrPresetWithBoardFlags},
+		{name: "Unknown preset", args: []string{"-preset", "impossible"}, err: game.ErrUnknownPreset},
+		{name: "Unknown locator", args: []string{"-locator", "impossible"}, err: game.ErrUnknownLocator},
+		{name: "Invalid number of rows", args: []string{"-rows", "0", "-cols", "6", "-holes", "7"},
+			err: board.ErrInvalidNumberOfRows},
+		{name: "Too many black holes", args: []string{"-rows", "2", "-cols", "2", "-holes", "4"},
+			err: game.ErrTooManyBlackHoles},
+		{name: "Density", args: []string{"-rows", "16", "-cols", "30", "-density", "20"}},
+		{name: "Holes and density", args: []string{"-rows", "16", "-cols", "30", "-holes", "5", "-density", "20"},
+			err: game.ErrBlackHoleCountAndDensity},
+		{name: "Density is too high", args: []string{"-rows", "2", "-cols", "2", "-density", "90"},
+			err: game.ErrTooManyBlackHoles},
+		{name: "Theme", args: []string{"-theme", "unicode", "-color", "never"}},
+		{name: "Unknown theme", args: []string{"-theme", "neon"}, err: render.ErrUnknownTheme},
+		{name: "Unknown format", args: []string{"-format", "yaml"}, err: render.ErrUnknownFormat},
+		{name: "Invalid color mode", args: []string{"-color", "sometimes"}, err: input.ErrInvalidColorMode},
+		{name: "Missing presets file", args: []string{"-presets", "/nonexistent/presets.json"}, err: os.ErrNotExist},
+		{name: "Too many black holes for a safe first click",
+			args: []string{"-rows", "2", "-cols", "2", "-holes", "3", "-first-click-safe"},
+			err:  game.ErrTooManyBlackHolesForSafeStart},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := input.ParseFlags(tc.args)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOptions_GameConfig(t *testing.T) {
+	opts, err := input.ParseFlags([]string{"-rows", "5", "-cols", "6", "-holes", "7", "-seed", "42", "-first-click-safe"})
+	require.NoError(t, err)
+	require.True(t, opts.DescribeBoard())
+
+	cfg, err := opts.GameConfig(nil)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 5, cfg.NumRows)
+	assert.EqualValues(t, 6, cfg.NumCols)
+	assert.EqualValues(t, 7, cfg.NumBlackHoles)
+	assert.True(t, cfg.FirstClickSafe)
+
+	another, err := opts.GameConfig(nil)
+	require.NoError(t, err)
+
+	// the same seed gives the same board
+	assert.EqualValues(t, cfg.BlackHoleLocator.LocateBlackHolesOnBoard(5, 6, 7),
+		another.BlackHoleLocator.LocateBlackHolesOnBoard(5, 6, 7))
+}
+
+func TestOptions_Terminal(t *testing.T) {
+	opts, err := input.ParseFlags([]string{"-theme", "emoji", "-color", "always", "-zero-based"})
+	require.NoError(t, err)
+
+	r := opts.Terminal(&strings.Builder{})
+	assert.Equal(t, render.Emoji.Name, r.Theme.Name)
+	assert.True(t, r.Color)
+	assert.True(t, r.Notation.ZeroBased)
+
+	// colors are used only on a terminal by default
+	opts, err = input.ParseFlags(nil)
+	require.NoError(t, err)
+
+	r = opts.Terminal(&strings.Builder{})
+	assert.Equal(t, render.ASCII.Name, r.Theme.Name)
+	assert.False(t, r.Color)
+	assert.IsType(t, render.Terminal{}, opts.Renderer(&strings.Builder{}))
+
+	opts, err = input.ParseFlags([]string{"-format", "JSON"})
+	require.NoError(t, err)
+	assert.IsType(t, render.JSON{}, opts.Renderer(&strings.Builder{}))
+}
+
+func TestParseFlags_UserDefinedPresets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "huge", "rows": 30, "cols": 50, "density": 18}]`), 0o600))
+
+	opts, err := input.ParseFlags([]string{"-presets", path, "-preset", "huge"})
+	require.NoError(t, err)
+	assert.Len(t, opts.Presets, len(game.Presets())+1)
+
+	cfg, err := opts.GameConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 270, cfg.BlackHoleCount())
+}
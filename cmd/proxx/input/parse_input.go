@@ -1,59 +1,100 @@
 package input
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"proxx/internal/proxx/command"
 	"proxx/internal/proxx/game"
 	"strconv"
 	"strings"
 )
 
 var (
-	ErrEmptyInput        = errors.New("empty input")
 	ErrValueIsNotInteger = errors.New("value should be an integer")
-	ErrTwoValuesExpected = errors.New("two values should be provided")
+	ErrValueIsNotNumber  = errors.New("value should be a number")
+	// ErrQuit is returned when the player asks to leave the game.
+	ErrQuit = errors.New("player left the game")
+	// ErrEndOfInput is returned when there is nothing more to read from the player.
+	ErrEndOfInput = errors.New("end of input")
 )
 
-func GetGameConfig() (game.Config, error) {
-	fmt.Println("Please, configure your game.")
-	fmt.Println("Enter a number of rows:")
+// Prompter asks the player questions: prompts are written to the writer,
+// answers are read line by line from the reader.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
 
-	var in string
+// NewPrompter creates a prompter that reads answers from r and writes prompts to w.
+func NewPrompter(r io.Reader, w io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(r), out: w}
+}
 
-	_, err := fmt.Scanln(&in)
+// readInput reads a line of the player's input.
+// Returns ErrEndOfInput if the input is exhausted and ErrQuit if the player wants to leave the game.
+func (p *Prompter) readInput() (string, error) {
+	line, err := p.in.ReadString('\n')
 	if err != nil {
-		fmt.Printf("Failed to read input: %s", err)
-		os.Exit(1)
+		if !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if line == "" {
+			return "", ErrEndOfInput
+		}
 	}
 
-	rowNum, err := integerFromString(in)
-	if err != nil {
-		return game.Config{}, fmt.Errorf("failed to get number of rows: %w", err)
+	line = strings.TrimSpace(line)
+
+	if exitTheGame(line) {
+		return "", ErrQuit
 	}
 
-	fmt.Println("Enter a number of columns:")
+	return line, nil
+}
+
+// GetGameConfig asks the player to choose one of the presets or to describe the board.
+func (p *Prompter) GetGameConfig(presets []game.Preset) (game.Config, error) {
+	fmt.Fprintln(p.out, "Please, configure your game.")
+	fmt.Fprintln(p.out, "Choose a preset by its number or name, or press ENTER to describe the board yourself:")
+
+	for i, preset := range presets {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, describePreset(preset))
+	}
 
-	_, err = fmt.Scanln(&in)
+	in, err := p.readInput()
 	if err != nil {
-		fmt.Printf("Failed to read input: %s", err)
-		os.Exit(1)
+		return game.Config{}, err
 	}
 
-	colNum, err := integerFromString(in)
+	if in != "" {
+		preset, err := presetFromString(in, presets)
+		if err != nil {
+			return game.Config{}, err
+		}
+
+		return preset.Config(game.NewUniformBlackHoleLocator()), nil
+	}
+
+	fmt.Fprintln(p.out, "Enter a number of rows:")
+
+	rowNum, err := p.readInteger()
 	if err != nil {
-		return game.Config{}, fmt.Errorf("failed to get number of columns: %w", err)
+		return game.Config{}, fmt.Errorf("failed to get number of rows: %w", err)
 	}
 
-	fmt.Println("Enter a number of black holes:")
+	fmt.Fprintln(p.out, "Enter a number of columns:")
 
-	_, err = fmt.Scanln(&in)
+	colNum, err := p.readInteger()
 	if err != nil {
-		fmt.Printf("Failed to read input: %s", err)
-		os.Exit(1)
+		return game.Config{}, fmt.Errorf("failed to get number of columns: %w", err)
 	}
 
-	bhNum, err := integerFromString(in)
+	fmt.Fprintln(p.out, "Enter a number of black holes or their density in percent (e.g. 15%):")
+
+	in, err = p.readInput()
 	if err != nil {
 		return game.Config{}, fmt.Errorf("failed to get number of black holes: %w", err)
 	}
@@ -61,78 +102,106 @@ func GetGameConfig() (game.Config, error) {
 	cfg := game.Config{
 		NumRows:          rowNum,
 		NumCols:          colNum,
-		NumBlackHoles:    bhNum,
 		BlackHoleLocator: game.NewUniformBlackHoleLocator(),
 	}
 
-	return cfg, nil
-}
+	if density, ok := strings.CutSuffix(in, "%"); ok {
+		cfg.BlackHoleDensity, err = strconv.ParseFloat(strings.TrimSpace(density), 64)
+		if err != nil {
+			return game.Config{}, fmt.Errorf("failed to get density of black holes: %w", ErrValueIsNotNumber)
+		}
 
-func integerFromString(in string) (int, error) {
-	if exitTheGame(in) {
-		os.Exit(0)
+		return cfg, nil
 	}
 
-	value, err := strconv.ParseInt(in, 10, 64)
+	cfg.NumBlackHoles, err = integerFromString(in)
 	if err != nil {
-		return 0, ErrValueIsNotInteger
+		return game.Config{}, fmt.Errorf("failed to get number of black holes: %w", err)
 	}
 
-	return int(value), nil
+	return cfg, nil
 }
 
-func GetCellCoordinates() (int, int, error) {
-	fmt.Println("Enter the coordinates of a cell you wish to open \n" +
-		"in a format \"rowNo,colNo\" (numeration starts from 1) and press ENTER:")
+// presetFromString returns the preset chosen by its number in the list or by its name.
+func presetFromString(in string, presets []game.Preset) (game.Preset, error) {
+	if n, err := strconv.Atoi(in); err == nil {
+		if n < 1 || n > len(presets) {
+			return game.Preset{}, fmt.Errorf("%w: %d", game.ErrUnknownPreset, n)
+		}
 
-	var in string
+		return presets[n-1], nil
+	}
 
-	_, err := fmt.Scanln(&in)
-	if err != nil {
-		fmt.Printf("Failed to read input: %s", err)
-		os.Exit(1)
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, in) {
+			return p, nil
+		}
 	}
 
-	if in == "" {
-		return 0, 0, ErrEmptyInput
+	return game.Preset{}, fmt.Errorf("%w: %q", game.ErrUnknownPreset, in)
+}
+
+func describePreset(p game.Preset) string {
+	if p.BlackHoleDensity > 0 {
+		return fmt.Sprintf("%s: %dx%d, %g%% black holes", p.Name, p.NumRows, p.NumCols, p.BlackHoleDensity)
 	}
 
-	if exitTheGame(in) {
-		os.Exit(0)
+	return fmt.Sprintf("%s: %dx%d, %d black holes", p.Name, p.NumRows, p.NumCols, p.NumBlackHoles)
+}
+
+func (p *Prompter) readInteger() (int, error) {
+	in, err := p.readInput()
+	if err != nil {
+		return 0, err
 	}
 
-	values := strings.Split(in, ",")
-	if len(values) != 2 {
-		return 0, 0, ErrTwoValuesExpected
+	return integerFromString(in)
+}
+
+func integerFromString(in string) (int, error) {
+	value, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		return 0, ErrValueIsNotInteger
 	}
 
-	row, err := strconv.ParseInt(values[0], 10, 64)
+	return int(value), nil
+}
+
+// GetCommand asks the player for the next command, positions are written in the notation.
+// Returns ErrQuit if the player wants to leave the game.
+func (p *Prompter) GetCommand(n command.Notation) (command.Command, error) {
+	fmt.Fprintln(p.out, "Enter a command, e.g. \"o 3,4\" or \"o D3\" to open the cell in row 3, column 4,\n"+
+		"or \"help\" to see all the commands:")
+
+	in, err := p.readInput()
 	if err != nil {
-		return 0, 0, ErrValueIsNotInteger
+		return command.Command{}, err
 	}
 
-	col, err := strconv.ParseInt(values[1], 10, 64)
+	cmd, err := n.Parse(in)
 	if err != nil {
-		return 0, 0, ErrValueIsNotInteger
+		return command.Command{}, err
 	}
 
-	return int(row - 1), int(col - 1), nil
+	if cmd.Kind == command.Quit {
+		return command.Command{}, ErrQuit
+	}
+
+	return cmd, nil
 }
 
 func exitTheGame(in string) bool {
 	return in == "Q" || in == "q"
 }
 
-func UserWantToPlayAnotherGame() bool {
-	fmt.Println("\nDo you want to play another game? Press 'Y/y' to continue:")
-
-	var in string
+// UserWantToPlayAnotherGame asks the player whether to play another game.
+func (p *Prompter) UserWantToPlayAnotherGame() (bool, error) {
+	fmt.Fprintln(p.out, "\nDo you want to play another game? Press 'Y/y' to continue:")
 
-	_, err := fmt.Scanln(&in)
+	in, err := p.readInput()
 	if err != nil {
-		fmt.Printf("Failed to read input: %s", err)
-		os.Exit(1)
+		return false, err
 	}
 
-	return in == "Y" || in == "y"
+	return in == "Y" || in == "y", nil
 }
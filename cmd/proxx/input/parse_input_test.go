@@ -0,0 +1,108 @@
+package input_test
+
+import (
+	"bytes"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrompter_GetCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       string
+		expected command.Command
+		err      error
+	}{
+		{name: "Open a cell", in: "o 3,4\n", expected: command.Command{Kind: command.Open, Position: board.Position{Row: 2, Col: 3}}},
+		{name: "Last line without a newline", in: "f 3,4", expected: command.Command{Kind: command.Flag, Position: board.Position{Row: 2, Col: 3}}},
+		{name: "Save", in: "save game.json\n", expected: command.Command{Kind: command.Save, Path: "game.json"}},
+		{name: "Invalid command", in: "open\n", err: command.ErrPositionExpected},
+		{name: "Quit", in: "q\n", err: input.ErrQuit},
+		{name: "Quit command", in: "quit\n", err: input.ErrQuit},
+		{name: "End of input", in: "", err: input.ErrEndOfInput},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &bytes.Buffer{}
+			p := input.NewPrompter(strings.NewReader(tc.in), out)
+
+			cmd, err := p.GetCommand(command.DefaultNotation)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cmd)
+			assert.Contains(t, out.String(), "Enter a command")
+		})
+	}
+}
+
+func TestPrompter_GetGameConfig(t *testing.T) {
+	presets := game.Presets()
+
+	testCases := []struct {
+		name     string
+		in       string
+		expected game.Config
+		err      error
+	}{
+		{name: "Preset by number", in: "2\n", expected: game.Config{NumRows: 16, NumCols: 16, NumBlackHoles: 40}},
+		{name: "Preset by name", in: "Expert\n", expected: game.Config{NumRows: 16, NumCols: 30, NumBlackHoles: 99}},
+		{name: "Board", in: "\n5\n6\n7\n", expected: game.Config{NumRows: 5, NumCols: 6, NumBlackHoles: 7}},
+		{name: "Density", in: "\n10\n10\n 15 %\n", expected: game.Config{NumRows: 10, NumCols: 10, BlackHoleDensity: 15}},
+		{name: "Unknown preset number", in: "4\n", err: game.ErrUnknownPreset},
+		{name: "Invalid number of rows", in: "\nfive\n", err: input.ErrValueIsNotInteger},
+		{name: "Invalid density", in: "\n5\n5\nmany%\n", err: input.ErrValueIsNotNumber},
+		{name: "Quit while describing the board", in: "\n5\nq\n", err: input.ErrQuit},
+		{name: "End of input", in: "\n5\n", err: input.ErrEndOfInput},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := input.NewPrompter(strings.NewReader(tc.in), &bytes.Buffer{})
+
+			cfg, err := p.GetGameConfig(presets)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, cfg.BlackHoleLocator)
+
+			cfg.BlackHoleLocator = nil
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestPrompter_UserWantToPlayAnotherGame(t *testing.T) {
+	p := input.NewPrompter(strings.NewReader("y\nn\n"), &bytes.Buffer{})
+
+	another, err := p.UserWantToPlayAnotherGame()
+	require.NoError(t, err)
+	assert.True(t, another)
+
+	another, err = p.UserWantToPlayAnotherGame()
+	require.NoError(t, err)
+	assert.False(t, another)
+
+	_, err = p.UserWantToPlayAnotherGame()
+	assert.ErrorIs(t, err, input.ErrEndOfInput)
+}
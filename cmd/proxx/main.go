@@ -1,77 +1,279 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"proxx/cmd/proxx/input"
-	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
 	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
 	"strings"
 )
 
 func main() {
-	fmt.Println("Press Q/q to leave the game.")
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[1], err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	opts, err := input.ParseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid flags: %s\n", err)
+		os.Exit(2)
+	}
+
+	c := &console{
+		prompter: input.NewPrompter(os.Stdin, os.Stdout),
+		out:      os.Stdout,
+		dataDir:  defaultDataDir(),
+	}
+
+	if opts.Format != render.FormatText {
+		// keep the standard output for the snapshots alone, so a script can read them
+		c.prompter = input.NewPrompter(os.Stdin, os.Stderr)
+		c.out = os.Stderr
+		c.snapshots = os.Stdout
+	}
+
+	err = c.run(opts)
+
+	switch {
+	case errors.Is(err, input.ErrQuit), errors.Is(err, input.ErrEndOfInput):
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(c.out, "Bye!")
+}
+
+// runCommand runs the command with the specified name.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "replay":
+		return runReplay(args)
+	case "tui":
+		return runTUI(args)
+	case "serve":
+		return runServe(args)
+	case "tcp":
+		return runTCP(args)
+	case "engine":
+		return runEngine(args)
+	case "arena":
+		return runArena(args)
+	case "simulate":
+		return runSimulate(args)
+	default:
+		return fmt.Errorf("unknown command, available commands: replay, tui, serve, tcp, engine, arena, simulate")
+	}
+}
+
+// console runs games in a terminal: the player is asked for input by the prompter and the games are printed to out.
+type console struct {
+	prompter *input.Prompter
+	out      io.Writer
+	// dataDir is the directory for autosaves and replays. If it's empty, the console doesn't touch files:
+	// games aren't saved, loaded or recorded, as for the players connected over the network.
+	dataDir string
+	// snapshots receives the boards written in a format other than text, out is used if it's nil.
+	snapshots io.Writer
+}
+
+// run plays games until the player doesn't want another one.
+// Returns input.ErrQuit or input.ErrEndOfInput if the player left, an unfinished game is saved before that.
+func (c *console) run(opts input.Options) error {
+	fmt.Fprintln(c.out, "Press Q/q to leave the game.")
 
 	for {
-		fmt.Printf("\n\n")
+		fmt.Fprintf(c.out, "\n\n")
+
+		gameCfg, err := opts.GameConfig(c.prompter)
+		if isLeaving(err) {
+			return err
+		}
 
-		gameCfg, err := input.GetGameConfig()
 		if err != nil {
-			fmt.Printf("Invalid game configuration: %s", err)
+			fmt.Fprintf(c.out, "Invalid game configuration: %s", err)
 			continue
 		}
 
 		proxx, err := game.NewGame(gameCfg)
 		if err != nil {
-			fmt.Printf("Failed to create a new game: %s", err)
+			fmt.Fprintf(c.out, "Failed to create a new game: %s", err)
 			continue
 		}
 
-		fmt.Println("Your game is ready!")
+		fmt.Fprintln(c.out, "Your game is ready!")
+
+		proxx, err = c.play(proxx, opts)
+		c.recordReplay(proxx)
+
+		if err != nil {
+			c.autosave(proxx)
+			fmt.Fprintf(c.out, "Hints used: %d\n", proxx.Stats().HintsUsed)
+
+			return err
+		}
+
+		if proxx.IsWon() {
+			fmt.Fprintln(c.out, "Great job, champion!")
+		} else {
+			fmt.Fprintln(c.out, "Oops! This time a Black Hole captured you!")
+		}
+
+		c.showBoard(proxx, opts)
+
+		if !proxx.IsWon() {
+			c.showReview(proxx, opts)
+		}
+
+		fmt.Fprintf(c.out, "Hints used: %d\n", proxx.Stats().HintsUsed)
+
+		another, err := c.prompter.UserWantToPlayAnotherGame()
+		if err != nil {
+			return err
+		}
+
+		if !another {
+			return nil
+		}
+	}
+}
 
-		for !proxx.IsOver() {
-			showBoardState(proxx.BoardState())
+// isLeaving returns true if the error means that the player left.
+func isLeaving(err error) bool {
+	return errors.Is(err, input.ErrQuit) || errors.Is(err, input.ErrEndOfInput)
+}
 
-			row, col, err := input.GetCellCoordinates()
+// play runs the game until it's over or the player leaves it.
+// Returns the game being played at the end (it changes when a game is loaded or a new one is started)
+// and input.ErrQuit or input.ErrEndOfInput if the player left.
+func (c *console) play(proxx *game.Game, opts input.Options) (*game.Game, error) {
+	cfg := command.Config{
+		NewGame: func() (*game.Game, error) {
+			cfg, err := opts.GameConfig(c.prompter)
 			if err != nil {
-				fmt.Printf("Failed to parse cell coordinates: %s", err)
-				continue
+				return nil, err
 			}
 
-			if err := proxx.OpenCell(row, col); err != nil {
-				fmt.Printf("Error opening the cell: %s", err)
-			}
+			return game.NewGame(cfg)
+		},
+		Notation: opts.Notation(),
+	}
+
+	if c.dataDir != "" {
+		cfg.Save = command.SaveFile
+		cfg.Load = command.LoadFile
+	}
+
+	d := command.NewDispatcher(proxx, cfg)
+
+	for !d.Game().IsOver() {
+		c.showBoard(d.Game(), opts)
+
+		cmd, err := c.prompter.GetCommand(opts.Notation())
+		if isLeaving(err) {
+			return d.Game(), err
 		}
 
-		if proxx.IsOver() {
-			if proxx.IsWon() {
-				fmt.Println("Great job, champion!")
-			} else {
-				fmt.Println("Oops! This time a Black Hole captured you!")
-			}
+		if err != nil {
+			fmt.Fprintf(c.out, "Failed to parse your command: %s\n", err)
+			continue
+		}
+
+		result, err := d.Execute(cmd)
+		if isLeaving(err) {
+			return d.Game(), err
 		}
 
-		showBoardState(proxx.BoardState())
+		if err != nil {
+			fmt.Fprintf(c.out, "Failed to %s: %s\n", cmd.Kind, err)
+			continue
+		}
 
-		if !input.UserWantToPlayAnotherGame() {
-			break
+		if result.Message != "" {
+			fmt.Fprintln(c.out, result.Message)
 		}
 	}
 
-	fmt.Println("Bye!")
+	return d.Game(), nil
 }
 
-func showBoardState(bs [][]board.CellValue) {
-	fmt.Println()
-	fmt.Println("Current state of the board:")
+// showBoard prints the board of the game in the format chosen by the options.
+// Boards in formats other than text are written as snapshots without any decoration.
+func (c *console) showBoard(g *game.Game, opts input.Options) {
+	if opts.Format == render.FormatText {
+		showBoardState(c.out, opts.Terminal(c.out), g)
+		return
+	}
 
-	builder := strings.Builder{}
+	w := c.snapshots
+	if w == nil {
+		w = c.out
+	}
 
-	for _, row := range bs {
-		for _, v := range row {
-			builder.WriteString(fmt.Sprintf("%s\t", v))
-		}
-		builder.WriteString("\n\n")
+	if err := opts.Renderer(w).Render(w, render.SnapshotOf(g)); err != nil {
+		fmt.Fprintf(c.out, "Failed to write the snapshot: %s\n", err)
+	}
+}
+
+// showBoardState prints the board of the game drawn by the renderer.
+func showBoardState(w io.Writer, r render.Terminal, g *game.Game) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Current state of the board:")
+
+	if err := r.Render(w, render.SnapshotOf(g)); err != nil {
+		fmt.Fprintf(w, "Failed to show the board: %s\n", err)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// autosave saves an unfinished game, so it can be loaded later with the "load" command.
+func (c *console) autosave(proxx *game.Game) {
+	if c.dataDir == "" || proxx.IsOver() || len(proxx.History()) == 0 {
+		return
+	}
+
+	path, err := c.dataFilePath("autosave.json")
+	if err != nil {
+		fmt.Fprintf(c.out, "Failed to save the game: %s\n", err)
+		return
+	}
+
+	if err := command.SaveFile(proxx, path); err != nil {
+		fmt.Fprintf(c.out, "Failed to save the game: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(c.out, "Your game is saved, type \"load %s\" to continue it.\n", path)
+}
+
+// defaultDataDir returns the game's directory in the user's cache directory.
+func defaultDataDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, "proxx")
+}
+
+// dataFilePath returns the path to a file in the game's data directory, missing directories are created.
+func (c *console) dataFilePath(elem ...string) (string, error) {
+	path := filepath.Join(append([]string{c.dataDir}, elem...)...)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
 	}
 
-	fmt.Println(builder.String())
+	return path, nil
 }
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSeed gives a board that can't be won by a single move.
+const testSeed = 1
+
+var testFlags = []string{"-rows", "4", "-cols", "4", "-holes", "2", "-seed", fmt.Sprint(testSeed), "-presets", ""}
+
+// newTestConsole creates a console that reads the script and keeps its data in a temporary directory.
+func newTestConsole(t *testing.T, script ...string) (*console, *bytes.Buffer) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	in := strings.NewReader(strings.Join(script, "\n") + "\n")
+
+	return &console{prompter: input.NewPrompter(in, out), out: out, dataDir: t.TempDir()}, out
+}
+
+func testOptions(t *testing.T) input.Options {
+	t.Helper()
+
+	opts, err := input.ParseFlags(testFlags)
+	require.NoError(t, err)
+
+	return opts
+}
+
+// testLayout returns the black holes placed on the board by the test flags.
+func testLayout(t *testing.T) []board.Position {
+	t.Helper()
+
+	return game.NewSeededUniformBlackHoleLocator(testSeed).LocateBlackHolesOnBoard(4, 4, 2)
+}
+
+// winningMoves returns the moves that open all the safe cells, skipping the ones opened by cascades.
+func winningMoves(t *testing.T) []string {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{NumRows: 4, NumCols: 4, NumBlackHoles: 2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator(testLayout(t))})
+	require.NoError(t, err)
+
+	var moves []string
+
+	for i := 0; i < 4 && !g.IsOver(); i++ {
+		for j := 0; j < 4 && !g.IsOver(); j++ {
+			if g.BoardState()[i][j] != board.CellValueUnknown || isBlackHole(testLayout(t), i, j) {
+				continue
+			}
+
+			_, err := g.OpenCell(i, j)
+			require.NoError(t, err)
+
+			moves = append(moves, fmt.Sprintf("%d,%d", i+1, j+1))
+		}
+	}
+
+	require.True(t, g.IsWon())
+	require.Greater(t, len(moves), 1)
+
+	return moves
+}
+
+func isBlackHole(layout []board.Position, row int, col int) bool {
+	for _, p := range layout {
+		if p.Row == row && p.Col == col {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestConsole_Run(t *testing.T) {
+	t.Run("Win and leave", func(t *testing.T) {
+		t.Parallel()
+
+		c, out := newTestConsole(t, append(winningMoves(t), "n")...)
+
+		require.NoError(t, c.run(testOptions(t)))
+		assert.Contains(t, out.String(), "Great job, champion!")
+		assert.Contains(t, out.String(), "The replay is saved")
+	})
+
+	t.Run("Lose and play another game until the input ends", func(t *testing.T) {
+		t.Parallel()
+
+		hole := testLayout(t)[0]
+		c, out := newTestConsole(t, fmt.Sprintf("%d,%d", hole.Row+1, hole.Col+1), "y")
+
+		err := c.run(testOptions(t))
+		assert.ErrorIs(t, err, input.ErrEndOfInput)
+		assert.Contains(t, out.String(), "Oops! This time a Black Hole captured you!")
+		assert.Contains(t, out.String(), "Review of your moves: 0 safe, 1 guess, 0 blunders.")
+		assert.Contains(t, out.String(), fmt.Sprintf("Move 1, open %d,%d: the fatal move, a guess with a 88%% chance to survive.",
+			hole.Row+1, hole.Col+1))
+		assert.Equal(t, 2, strings.Count(out.String(), "Your game is ready!"))
+	})
+
+	t.Run("Invalid commands don't stop the game", func(t *testing.T) {
+		t.Parallel()
+
+		script := []string{"", "1", "falg 1,1", "9,9", "save", "redo", "c 1,1"}
+		c, out := newTestConsole(t, append(script, winningMoves(t)...)...)
+
+		require.ErrorIs(t, c.run(testOptions(t)), input.ErrEndOfInput)
+		assert.Contains(t, out.String(), command.ErrEmptyCommand.Error())
+		assert.Contains(t, out.String(), command.ErrInvalidPosition.Error())
+		assert.Contains(t, out.String(), `did you mean "flag"?`)
+		assert.Contains(t, out.String(), game.ErrCellPositionIsOutsideBoard.Error())
+		assert.Contains(t, out.String(), command.ErrFileNameExpected.Error())
+		assert.Contains(t, out.String(), game.ErrNothingToRedo.Error())
+		assert.Contains(t, out.String(), game.ErrCellNotOpen.Error())
+		assert.Contains(t, out.String(), "Great job, champion!")
+	})
+
+	t.Run("Flag, undo, redo, stats and help", func(t *testing.T) {
+		t.Parallel()
+
+		hole := testLayout(t)[0]
+		flag := fmt.Sprintf("f %d,%d", hole.Row+1, hole.Col+1)
+		script := append([]string{flag, "u", "r", "stats", "help"}, winningMoves(t)...)
+		c, out := newTestConsole(t, append(script, "n")...)
+
+		require.NoError(t, c.run(testOptions(t)))
+		assert.Contains(t, out.String(), "flags: 1 of 2 black holes")
+		assert.Contains(t, out.String(), "undos: 1")
+		assert.Contains(t, out.String(), "Commands")
+		assert.Contains(t, out.String(), "Great job, champion!")
+	})
+
+	t.Run("New game in the middle of the game", func(t *testing.T) {
+		t.Parallel()
+
+		moves := winningMoves(t)
+		c, out := newTestConsole(t, append([]string{moves[0], "new"}, moves...)...)
+
+		require.ErrorIs(t, c.run(testOptions(t)), input.ErrEndOfInput)
+		assert.Equal(t, 2, strings.Count(out.String(), "Your game is ready!"))
+		assert.Contains(t, out.String(), "Great job, champion!")
+	})
+
+	t.Run("Quit saves the unfinished game", func(t *testing.T) {
+		t.Parallel()
+
+		moves := winningMoves(t)
+		c, out := newTestConsole(t, moves[0], "hint", "q")
+
+		require.ErrorIs(t, c.run(testOptions(t)), input.ErrQuit)
+		assert.Contains(t, out.String(), "Hints used: 1")
+
+		saved, err := command.LoadFile(filepath.Join(c.dataDir, "autosave.json"))
+		require.NoError(t, err)
+		assert.Len(t, saved.History(), 1)
+		assert.Equal(t, 1, saved.Stats().HintsUsed)
+	})
+
+	t.Run("End of input saves the unfinished game", func(t *testing.T) {
+		t.Parallel()
+
+		moves := winningMoves(t)
+		c, _ := newTestConsole(t, moves[0])
+
+		require.ErrorIs(t, c.run(testOptions(t)), input.ErrEndOfInput)
+
+		_, err := os.Stat(filepath.Join(c.dataDir, "autosave.json"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Save and load during the game", func(t *testing.T) {
+		t.Parallel()
+
+		moves := winningMoves(t)
+		path := filepath.Join(t.TempDir(), "game.json")
+		script := append([]string{moves[0], "save " + path, "load " + path}, moves[1:]...)
+		c, out := newTestConsole(t, append(script, "n")...)
+
+		require.NoError(t, c.run(testOptions(t)))
+		assert.Contains(t, out.String(), "The game is loaded from "+path)
+		assert.Contains(t, out.String(), "Great job, champion!")
+	})
+
+	t.Run("Zero-based notation", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := input.ParseFlags(append(testFlags, "-zero-based"))
+		require.NoError(t, err)
+
+		hole := testLayout(t)[0]
+		c, out := newTestConsole(t, fmt.Sprintf("o %s%d", command.ColumnLetters(hole.Col), hole.Row))
+
+		require.ErrorIs(t, c.run(opts), input.ErrEndOfInput)
+		assert.Contains(t, out.String(), "   A B C D\n   0 1 2 3\n")
+		assert.Contains(t, out.String(), "\n0  ? ? ? ?\n")
+		assert.Contains(t, out.String(), "Oops! This time a Black Hole captured you!")
+	})
+
+	t.Run("Theme and colors", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := input.ParseFlags(append(testFlags, "-theme", "unicode", "-color", "always"))
+		require.NoError(t, err)
+
+		hole := testLayout(t)[0]
+		c, out := newTestConsole(t, fmt.Sprintf("o %d,%d", hole.Row+1, hole.Col+1))
+
+		require.ErrorIs(t, c.run(opts), input.ErrEndOfInput)
+		assert.Contains(t, out.String(), "  ┌─────────┐\n")
+		assert.Contains(t, out.String(), "\x1b[1;97;41m✹\x1b[0m")
+		assert.Contains(t, out.String(), "Oops! This time a Black Hole captured you!")
+	})
+
+	t.Run("JSON snapshots after each move", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := input.ParseFlags(append(testFlags, "-format", "json"))
+		require.NoError(t, err)
+
+		moves := winningMoves(t)
+		c, out := newTestConsole(t, append(moves, "n")...)
+
+		snapshots := &bytes.Buffer{}
+		c.snapshots = snapshots
+
+		require.NoError(t, c.run(opts))
+		assert.Contains(t, out.String(), "Great job, champion!")
+		assert.NotContains(t, out.String(), "Current state of the board")
+
+		lines := strings.Split(strings.TrimSpace(snapshots.String()), "\n")
+		require.Len(t, lines, len(moves)+1)
+
+		var last struct {
+			Status string              `json:"status"`
+			Cells  [][]board.CellValue `json:"cells"`
+		}
+
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+		assert.Equal(t, game.StatusWon.String(), last.Status)
+		assert.Len(t, last.Cells, 4)
+	})
+
+	t.Run("Preset chosen interactively", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := input.ParseFlags([]string{"-presets", ""})
+		require.NoError(t, err)
+
+		c, out := newTestConsole(t, "unknown", "beginner", "q")
+
+		require.ErrorIs(t, c.run(opts), input.ErrQuit)
+		assert.Contains(t, out.String(), game.ErrUnknownPreset.Error())
+		assert.Contains(t, out.String(), "Your game is ready!")
+	})
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"proxx/internal/proxx/replay"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxReplayPause limits the pause between moves, so long thinking doesn't stall the playback.
+const maxReplayPause = 3 * time.Second
+
+// runReplay implements the "proxx replay [flags] <file>" command.
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1, "playback speed, 2 plays twice as fast")
+	step := fs.Bool("step", false, "step through the moves manually")
+	check := fs.Bool("check", false, "only check that the recorded result matches the moves")
+	themeName := fs.String("theme", render.ASCII.Name, "look of the board: ascii, unicode or emoji")
+	zeroBased := fs.Bool("zero-based", false, "number rows and columns from 0")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: proxx replay [-speed N] [-step] [-check] [-theme NAME] [-zero-based] <file>")
+	}
+
+	theme, err := render.ThemeByName(*themeName)
+	if err != nil {
+		return err
+	}
+
+	if *speed <= 0 {
+		return errors.New("speed should be positive")
+	}
+
+	r, err := readReplay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		if err := r.Check(); err != nil {
+			return err
+		}
+
+		fmt.Printf("The replay is valid: %d moves, the game is %s.\n", len(r.Moves), r.Result)
+		return nil
+	}
+
+	p, err := replay.NewPlayer(r)
+	if err != nil {
+		return err
+	}
+
+	notation := command.Notation{ZeroBased: *zeroBased}
+	renderer := render.NewTerminal(os.Stdout, theme, notation)
+
+	showReplayFrame(p, renderer, notation)
+
+	if *step {
+		return stepThroughReplay(p, renderer, notation)
+	}
+
+	var previous time.Duration
+
+	for p.Step() < p.Len() {
+		next, _ := p.Next()
+		time.Sleep(min(time.Duration(float64(next.Elapsed-previous) / *speed), maxReplayPause))
+		previous = next.Elapsed
+
+		if _, err := p.Forward(); err != nil {
+			return err
+		}
+
+		showReplayFrame(p, renderer, notation)
+	}
+
+	fmt.Printf("The game is %s.\n", p.Game().Status())
+
+	return nil
+}
+
+// stepThroughReplay moves through the replay following the player's commands.
+func stepThroughReplay(p *replay.Player, renderer render.Terminal, notation command.Notation) error {
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for {
+		fmt.Println("ENTER/n - next move, p - previous move, g <N> - go to move N, q - quit:")
+
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		action := ""
+		if len(fields) > 0 {
+			action = fields[0]
+		}
+
+		var err error
+
+		switch action {
+		case "", "n":
+			_, err = p.Forward()
+		case "p":
+			err = p.Backward()
+		case "g":
+			if len(fields) != 2 {
+				err = errors.New("move number should be provided")
+				break
+			}
+
+			var n int
+			if n, err = strconv.Atoi(fields[1]); err == nil {
+				err = p.Seek(n)
+			}
+		case "q":
+			return nil
+		default:
+			err = fmt.Errorf("unknown command %q", action)
+		}
+
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			continue
+		}
+
+		showReplayFrame(p, renderer, notation)
+	}
+}
+
+// showReplayFrame prints the last played move with its recorded time and the board after it.
+func showReplayFrame(p *replay.Player, renderer render.Terminal, notation command.Notation) {
+	m, err := p.Last()
+
+	switch {
+	case err != nil:
+		fmt.Printf("\nMove 0/%d: the game starts\n", p.Len())
+	case m.Kind == game.MoveResign:
+		fmt.Printf("\nMove %d/%d at %s: %s\n", p.Step(), p.Len(), formatElapsed(m.Elapsed), m.Kind)
+	default:
+		fmt.Printf("\nMove %d/%d at %s: %s %s\n", p.Step(), p.Len(), formatElapsed(m.Elapsed),
+			m.Kind, notation.FormatPosition(m.Position))
+	}
+
+	showBoardState(os.Stdout, renderer, p.Game())
+}
+
+func readReplay(path string) (replay.Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return replay.Replay{}, err
+	}
+	defer f.Close()
+
+	return replay.Read(f)
+}
+
+// recordReplay saves the replay of the game, so it can be watched later with "proxx replay".
+func (c *console) recordReplay(proxx *game.Game) {
+	if c.dataDir == "" || len(proxx.History()) == 0 {
+		return
+	}
+
+	path, err := c.dataFilePath("replays", time.Now().Format("20060102-150405")+".json")
+	if err != nil {
+		fmt.Fprintf(c.out, "Failed to record the replay: %s\n", err)
+		return
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(c.out, "Failed to record the replay: %s\n", err)
+		return
+	}
+
+	if err := replay.Record(proxx).Write(f); err != nil {
+		_ = f.Close()
+		fmt.Fprintf(c.out, "Failed to record the replay: %s\n", err)
+		return
+	}
+
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(c.out, "Failed to record the replay: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(c.out, "The replay is saved, watch it with \"proxx replay %s\".\n", path)
+}
+
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
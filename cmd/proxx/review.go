@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/review"
+)
+
+// showReview prints the review of a lost game: the risky moves and the move that hit a black hole.
+func (c *console) showReview(g *game.Game, opts input.Options) {
+	report, err := review.Game(g)
+	if err != nil {
+		fmt.Fprintf(c.out, "Failed to review the game: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(c.out, "Review of your moves: %d safe, %d %s, %d %s.\n",
+		report.Count(review.VerdictSafe),
+		report.Count(review.VerdictGuess), plural(report.Count(review.VerdictGuess), "guess", "guesses"),
+		report.Count(review.VerdictBlunder), plural(report.Count(review.VerdictBlunder), "blunder", "blunders"))
+
+	n := opts.Notation()
+
+	for i, m := range report.Moves {
+		var verdict string
+
+		switch m.Verdict {
+		case review.VerdictGuess:
+			verdict = fmt.Sprintf("a guess with a %.0f%% chance to survive", m.Survival*100)
+		case review.VerdictBlunder:
+			verdict = fmt.Sprintf("a blunder, the cell at %s was safe; the chance to survive was %.0f%%",
+				n.FormatPosition(m.SafeCell), m.Survival*100)
+		default:
+			continue
+		}
+
+		if i == report.Fatal {
+			verdict = "the fatal move, " + verdict
+		}
+
+		fmt.Fprintf(c.out, "  Move %d, %s %s: %s.\n", i+1, m.Move.Kind, n.FormatPosition(m.Move.Position), verdict)
+	}
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return one
+	}
+
+	return many
+}
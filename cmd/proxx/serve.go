@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/server"
+	"proxx/internal/proxx/session"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout limits the time given to the requests in flight when the server stops.
+const shutdownTimeout = 5 * time.Second
+
+// runServe implements the "proxx serve [flags]" command: games are hosted by an HTTP server until it's interrupted.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", ":8080", "address to listen on")
+	presetsFile := fs.String("presets", "", "JSON file with user-defined presets")
+	ttl := fs.Duration("ttl", session.DefaultTTL, "time after the last move when an idle game is dropped")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	custom, err := readPresets(*presetsFile)
+	if err != nil {
+		return err
+	}
+
+	if *ttl <= 0 {
+		return errors.New("ttl should be positive")
+	}
+
+	sessions := session.NewManager(session.Config{TTL: *ttl})
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           server.New(server.Config{CustomPresets: custom, Sessions: sessions}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go sessions.Run(ctx, min(*ttl, time.Minute))
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	fmt.Printf("Serving games on %s, open it in a browser to play, press Ctrl-C to stop.\n", *listen)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// readPresets reads the user-defined presets from the file, there are none if the path is empty.
+func readPresets(path string) ([]game.Preset, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	custom, err := game.ReadPresets(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return custom, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"proxx/cmd/proxx/input"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/simulation"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// histogramWidth is the length of the longest bar of the progress histogram.
+	histogramWidth = 40
+	// maxShownGuesses groups the games with more forced guesses into a single line.
+	maxShownGuesses = 5
+)
+
+// runSimulate implements the "proxx simulate [flags] [-- game flags]" command: the built-in bot plays many games
+// and their statistics are printed.
+func runSimulate(args []string) error {
+	var gameArgs []string
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, gameArgs = args[:i], args[i+1:]
+	}
+
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	games := fs.Int("games", 1000, "number of games")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of games played at the same time")
+	seed := fs.Int64("seed", 1, "master seed, game i uses the seed plus i")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 0 {
+		return errors.New("usage: proxx simulate [-games N] [-workers N] [-seed N] [-- game flags]")
+	}
+
+	opts, err := input.ParseFlags(gameArgs)
+	if err != nil {
+		return err
+	}
+
+	if !opts.DescribeBoard() {
+		opts.Preset = "beginner"
+	}
+
+	cfg, err := opts.GameConfig(nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+
+	result, err := simulation.Run(ctx, simulation.Config{
+		Games:   *games,
+		Workers: *workers,
+		Seed:    *seed,
+		GameConfig: func(seed int64) (game.Config, error) {
+			locator, err := game.NewBlackHoleLocator(opts.Locator, seed)
+			if err != nil {
+				return game.Config{}, err
+			}
+
+			gameCfg := cfg
+			gameCfg.BlackHoleLocator = locator
+
+			return gameCfg, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d games on a %dx%d board with %d black holes, %s locator, first click %s, seed %d.\n",
+		*games, cfg.NumRows, cfg.NumCols, cfg.BlackHoleCount(), opts.Locator, firstClickRule(cfg.FirstClickSafe), *seed)
+	fmt.Printf("Played in %s by %d workers.\n\n", time.Since(start).Round(time.Millisecond), *workers)
+
+	showSimulation(os.Stdout, result)
+
+	return nil
+}
+
+func firstClickRule(safe bool) string {
+	if safe {
+		return "safe"
+	}
+
+	return "unprotected"
+}
+
+// showSimulation prints the win rate, the forced guesses and the histogram of the progress.
+func showSimulation(w io.Writer, r simulation.Result) {
+	low, high := r.WinRateInterval()
+
+	fmt.Fprintf(w, "Win rate: %.2f%% (%d of %d), 95%% confidence interval %.2f%%-%.2f%%\n",
+		r.WinRate()*100, r.Wins(), len(r.Games), low*100, high*100)
+	fmt.Fprintf(w, "Forced guesses: %d, %.2f per game\n\n", r.Guesses(), float64(r.Guesses())/float64(len(r.Games)))
+
+	fmt.Fprintln(w, "Forced guesses  Games      Won")
+
+	games, wins := r.GuessCounts(maxShownGuesses)
+	for i := range games {
+		label := fmt.Sprint(i)
+		if i == maxShownGuesses {
+			label += "+"
+		}
+
+		fmt.Fprintf(w, "%14s  %5d  %6.2f%%\n", label, games[i], percent(wins[i], games[i]))
+	}
+
+	fmt.Fprintln(w, "\nCells opened")
+
+	h := r.ProgressHistogram()
+
+	most := 0
+	for _, n := range h {
+		most = max(most, n)
+	}
+
+	for i, n := range h {
+		bar := strings.Repeat("#", (n*histogramWidth+most-1)/max(most, 1))
+		fmt.Fprintf(w, "%3d-%3d%%  %-*s %d\n", i*100/simulation.HistogramBuckets, (i+1)*100/simulation.HistogramBuckets,
+			histogramWidth, bar, n)
+	}
+}
+
+func percent(n int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(n) * 100 / float64(total)
+}
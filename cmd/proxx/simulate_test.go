@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"proxx/internal/proxx/simulation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSimulate_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		"positional args":          {"expert"},
+		"game flags without --":    {"-preset", "expert"},
+		"preset and numbers":       {"--", "-preset", "expert", "-rows", "9"},
+		"incomplete board":         {"--", "-rows", "9", "-cols", "9"},
+		"unknown preset":           {"--", "-preset", "nope"},
+		"unknown locator":          {"--", "-locator", "nope"},
+		"too many black holes":     {"--", "-rows", "2", "-cols", "2", "-holes", "4"},
+		"no games":                 {"-games", "0"},
+		"no workers":               {"-workers", "0"},
+		"no room for a safe start": {"--", "-rows", "2", "-cols", "1", "-holes", "1", "-first-click-safe"},
+	}
+
+	for name, args := range tests {
+		args := args
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Error(t, runSimulate(args))
+		})
+	}
+}
+
+func TestShowSimulation(t *testing.T) {
+	t.Parallel()
+
+	r := simulation.Result{Games: []simulation.GameResult{
+		{Won: true, Opened: 10, Free: 10},
+		{Won: true, Guesses: 1, Opened: 10, Free: 10},
+		{Guesses: 7, Opened: 5, Free: 10},
+		{Opened: 0, Free: 10},
+	}}
+
+	var out bytes.Buffer
+
+	showSimulation(&out, r)
+
+	assert.Equal(t, `Win rate: 50.00% (2 of 4), 95% confidence interval 15.00%-85.00%
+Forced guesses: 8, 2.00 per game
+
+Forced guesses  Games      Won
+             0      2   50.00%
+             1      1  100.00%
+             2      0    0.00%
+             3      0    0.00%
+             4      0    0.00%
+            5+      1    0.00%
+
+Cells opened
+  0- 10%  ####################                     1
+ 10- 20%                                           0
+ 20- 30%                                           0
+ 30- 40%                                           0
+ 40- 50%                                           0
+ 50- 60%  ####################                     1
+ 60- 70%                                           0
+ 70- 80%                                           0
+ 80- 90%                                           0
+ 90-100%  ######################################## 2
+`, out.String())
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"proxx/cmd/proxx/input"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// tcpWriteTimeout limits the time of writing to a player, so a client that doesn't read can't block its game.
+	tcpWriteTimeout = 10 * time.Second
+	// tcpShutdownTimeout limits the time given to the players to be told the server is going down.
+	tcpShutdownTimeout = 5 * time.Second
+)
+
+// runTCP implements the "proxx tcp [flags] [-- game flags]" command: every connection gets its own game
+// played with the same prompts as in the terminal, e.g. with netcat or telnet.
+func runTCP(args []string) error {
+	fs := flag.NewFlagSet("tcp", flag.ContinueOnError)
+	listen := fs.String("listen", ":4000", "address to listen on")
+	maxConns := fs.Int("max-conns", 16, "maximum number of players connected at the same time")
+	idleTimeout := fs.Duration("idle-timeout", 5*time.Minute, "time a player may think before being disconnected")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *maxConns <= 0 {
+		return errors.New("max-conns should be positive")
+	}
+
+	if *idleTimeout <= 0 {
+		return errors.New("idle-timeout should be positive")
+	}
+
+	opts, err := input.ParseFlags(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Serving games on %s, press Ctrl-C to stop.\n", l.Addr())
+
+	s := newTCPServer(opts, *maxConns, *idleTimeout)
+
+	return s.serve(ctx, l)
+}
+
+// tcpServer gives every connection its own console. The number of connections is limited,
+// a player who doesn't type anything for the idle timeout is disconnected.
+type tcpServer struct {
+	opts        input.Options
+	maxConns    int
+	idleTimeout time.Duration
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+func newTCPServer(opts input.Options, maxConns int, idleTimeout time.Duration) *tcpServer {
+	return &tcpServer{opts: opts, maxConns: maxConns, idleTimeout: idleTimeout, conns: make(map[net.Conn]struct{})}
+}
+
+// serve accepts connections until the context is done. Then the players are told the server is going down,
+// their connections are closed and serve waits for the games to end.
+func (s *tcpServer) serve(ctx context.Context, l net.Listener) error {
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-stopped:
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				s.shutdown()
+				return nil
+			}
+
+			l.Close()
+			s.shutdown()
+
+			return err
+		}
+
+		if !s.add(conn) {
+			s.reject(conn)
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			defer s.remove(conn)
+
+			s.handle(conn)
+		}()
+	}
+}
+
+// add registers the connection unless the limit is reached.
+func (s *tcpServer) add(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.conns) >= s.maxConns {
+		return false
+	}
+
+	s.conns[conn] = struct{}{}
+
+	return true
+}
+
+func (s *tcpServer) remove(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+
+	conn.Close()
+}
+
+func (s *tcpServer) reject(conn net.Conn) {
+	_ = conn.SetWriteDeadline(time.Now().Add(tcpWriteTimeout))
+	fmt.Fprintln(conn, "Sorry, all the seats are taken, try again later.")
+	conn.Close()
+}
+
+// shutdown tells the players the server is going down and closes their connections.
+func (s *tcpServer) shutdown() {
+	s.mu.Lock()
+
+	for conn := range s.conns {
+		_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+		fmt.Fprintln(conn, "\nThe server is going down, bye!")
+		conn.Close()
+	}
+
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(tcpShutdownTimeout):
+	}
+}
+
+// handle plays games with the player until they leave or the connection breaks.
+func (s *tcpServer) handle(conn net.Conn) {
+	in := &connReader{conn: conn, timeout: s.idleTimeout}
+	out := &connWriter{conn: conn}
+
+	fmt.Fprintln(out, "Welcome to Proxx!")
+
+	c := &console{prompter: input.NewPrompter(in, out), out: out}
+
+	if err := c.run(s.opts); err != nil && !isLeaving(err) {
+		fmt.Fprintf(out, "%s\n", err)
+	}
+
+	if errors.Is(in.err, os.ErrDeadlineExceeded) {
+		fmt.Fprintf(out, "\nYou have been idle for %s, bye!\n", s.idleTimeout)
+		return
+	}
+
+	fmt.Fprintln(out, "Bye!")
+}
+
+// connReader reads the player's input, every read must complete within the timeout.
+// Errors of the connection end the input, so the console stops the way it does at the end of a file.
+type connReader struct {
+	conn    net.Conn
+	timeout time.Duration
+	// err is the error that ended the input.
+	err error
+}
+
+func (r *connReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, io.EOF
+	}
+
+	_ = r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+
+	n, err := r.conn.Read(p)
+	if err != nil {
+		r.err = err
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// connWriter writes to the player, every write must complete within tcpWriteTimeout.
+type connWriter struct {
+	conn net.Conn
+}
+
+func (w *connWriter) Write(p []byte) (int, error) {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(tcpWriteTimeout))
+	return w.conn.Write(p)
+}
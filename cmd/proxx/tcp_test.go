@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"proxx/internal/proxx/command"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTCPServer serves games on a random port until the test ends and returns the address and the result of serve.
+func startTCPServer(t *testing.T, s *tcpServer) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- s.serve(ctx, l)
+	}()
+
+	return l.Addr().String(), cancel, errs
+}
+
+func dialTCP(t *testing.T, addr string) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+
+	return conn
+}
+
+// readUntil reads from the connection until the output contains the text and returns the output read.
+func readUntil(t *testing.T, r *bufio.Reader, text string) string {
+	t.Helper()
+
+	var out strings.Builder
+
+	for !strings.Contains(out.String(), text) {
+		b, err := r.ReadByte()
+		require.NoError(t, err, "%q isn't found in %q", text, out.String())
+		out.WriteByte(b)
+	}
+
+	return out.String()
+}
+
+func TestTCPServer(t *testing.T) {
+	t.Run("Players have their own games", func(t *testing.T) {
+		t.Parallel()
+
+		addr, _, _ := startTCPServer(t, newTCPServer(testOptions(t), 2, time.Minute))
+
+		winner := dialTCP(t, addr)
+		loser := dialTCP(t, addr)
+
+		hole := testLayout(t)[0]
+		fmt.Fprintf(loser, "%d,%d\nn\n", hole.Row+1, hole.Col+1)
+
+		for _, m := range winningMoves(t) {
+			fmt.Fprintln(winner, m)
+		}
+
+		fmt.Fprintln(winner, "n")
+
+		out, err := io.ReadAll(winner)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "Welcome to Proxx!")
+		assert.Contains(t, string(out), "Great job, champion!")
+		assert.True(t, strings.HasSuffix(string(out), "Bye!\n"))
+		assert.NotContains(t, string(out), "replay is saved")
+
+		out, err = io.ReadAll(loser)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "Oops! This time a Black Hole captured you!")
+	})
+
+	t.Run("Files can't be saved or loaded", func(t *testing.T) {
+		t.Parallel()
+
+		addr, _, _ := startTCPServer(t, newTCPServer(testOptions(t), 1, time.Minute))
+
+		conn := dialTCP(t, addr)
+		fmt.Fprintln(conn, "f 1,1\nsave /tmp/game.json\nload /etc/passwd\nq")
+
+		out, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(out), command.ErrUnsupported.Error()))
+		assert.NotContains(t, string(out), "Your game is saved")
+	})
+
+	t.Run("Connection limit", func(t *testing.T) {
+		t.Parallel()
+
+		addr, _, _ := startTCPServer(t, newTCPServer(testOptions(t), 1, time.Minute))
+
+		first := bufio.NewReader(dialTCP(t, addr))
+		readUntil(t, first, "Welcome to Proxx!")
+
+		out, err := io.ReadAll(dialTCP(t, addr))
+		require.NoError(t, err)
+		assert.Equal(t, "Sorry, all the seats are taken, try again later.\n", string(out))
+	})
+
+	t.Run("Idle players are disconnected", func(t *testing.T) {
+		t.Parallel()
+
+		addr, _, _ := startTCPServer(t, newTCPServer(testOptions(t), 1, 100*time.Millisecond))
+
+		out, err := io.ReadAll(dialTCP(t, addr))
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "You have been idle for 100ms, bye!")
+
+		// the seat is free again
+		r := bufio.NewReader(dialTCP(t, addr))
+		readUntil(t, r, "Welcome to Proxx!")
+	})
+
+	t.Run("Shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		addr, cancel, errs := startTCPServer(t, newTCPServer(testOptions(t), 2, time.Minute))
+
+		conn := dialTCP(t, addr)
+		r := bufio.NewReader(conn)
+		readUntil(t, r, "Enter a command")
+
+		cancel()
+
+		readUntil(t, r, "The server is going down, bye!")
+
+		_, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		select {
+		case err := <-errs:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the server doesn't stop")
+		}
+
+		_, err = net.Dial("tcp", addr)
+		assert.Error(t, err)
+	})
+}
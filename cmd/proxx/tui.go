@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"proxx/cmd/proxx/input"
+	"proxx/cmd/proxx/tui"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"time"
+)
+
+// runTUI implements the "proxx tui [flags]" command: the game is played in the full-screen mode.
+// It takes the same flags as the game itself, the board is asked for before the screen is switched.
+func runTUI(args []string) error {
+	opts, err := input.ParseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	c := &console{
+		prompter: input.NewPrompter(os.Stdin, os.Stdout),
+		out:      os.Stdout,
+		dataDir:  defaultDataDir(),
+	}
+
+	cfg, err := opts.GameConfig(c.prompter)
+	if err != nil {
+		return err
+	}
+
+	proxx, err := game.NewGame(cfg)
+	if err != nil {
+		return err
+	}
+
+	proxx, err = tui.Run(proxx, command.Config{
+		NewGame: func() (*game.Game, error) {
+			// the same board with black holes placed anew
+			locator, err := game.NewBlackHoleLocator(opts.Locator, time.Now().UnixNano())
+			if err != nil {
+				return nil, err
+			}
+
+			cfg.BlackHoleLocator = locator
+
+			return game.NewGame(cfg)
+		},
+		Save:     command.SaveFile,
+		Load:     command.LoadFile,
+		Notation: opts.Notation(),
+	}, opts.Terminal(os.Stdout), os.Stdin, os.Stdout)
+
+	c.recordReplay(proxx)
+	c.autosave(proxx)
+
+	return err
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyKind represents a kind of key pressed by the player.
+type KeyKind int
+
+const (
+	KeyUnknown KeyKind = iota
+	KeyRune
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyInterrupt
+	KeyMouse
+)
+
+// Key represents a key pressed by the player. Rune is set for KeyRune, Mouse for KeyMouse.
+type Key struct {
+	Kind  KeyKind
+	Rune  rune
+	Mouse MouseEvent
+}
+
+// MouseButton represents a mouse button.
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseRelease
+	MouseWheelUp
+	MouseWheelDown
+	MouseOther
+)
+
+// MouseEvent represents a press or a release of a mouse button at the 1-based terminal column X and line Y.
+// Legacy reports don't tell which button is released: Button is MouseRelease for them.
+type MouseEvent struct {
+	Button  MouseButton
+	X       int
+	Y       int
+	Pressed bool
+}
+
+const (
+	escape    = 0x1b
+	ctrlC     = 0x03
+	backspace = 0x7f
+	ctrlH     = 0x08
+)
+
+// readKey reads a single key from the terminal in raw mode.
+// Arrow keys arrive as escape sequences, a lone escape is the Escape key.
+// Unsupported escape sequences are consumed and reported as KeyUnknown.
+func readKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case ctrlC:
+		return Key{Kind: KeyInterrupt}, nil
+	case '\r', '\n':
+		return Key{Kind: KeyEnter}, nil
+	case backspace, ctrlH:
+		return Key{Kind: KeyBackspace}, nil
+	case escape:
+		if r.Buffered() == 0 {
+			return Key{Kind: KeyEscape}, nil
+		}
+
+		return readEscapeSequence(r)
+	}
+
+	if b < utf8.RuneSelf {
+		return Key{Kind: KeyRune, Rune: rune(b)}, nil
+	}
+
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{Kind: KeyRune, Rune: ch}, nil
+}
+
+// readEscapeSequence reads the rest of a sequence started by the escape: "[A" or "OA" for arrows, for example.
+func readEscapeSequence(r *bufio.Reader) (Key, error) {
+	introducer, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	if introducer != '[' && introducer != 'O' {
+		return Key{Kind: KeyUnknown}, nil
+	}
+
+	// parameters and intermediate bytes go before the final byte in the range 0x40-0x7e
+	var (
+		final  byte
+		params []byte
+	)
+
+	for {
+		if final, err = r.ReadByte(); err != nil {
+			return Key{}, err
+		}
+
+		if final >= 0x40 && final <= 0x7e {
+			break
+		}
+
+		params = append(params, final)
+	}
+
+	if introducer == '[' && final == 'M' && len(params) == 0 {
+		return readLegacyMouse(r)
+	}
+
+	if introducer == '[' && (final == 'M' || final == 'm') && len(params) > 0 && params[0] == '<' {
+		return parseSGRMouse(string(params[1:]), final == 'M'), nil
+	}
+
+	switch final {
+	case 'A':
+		return Key{Kind: KeyUp}, nil
+	case 'B':
+		return Key{Kind: KeyDown}, nil
+	case 'C':
+		return Key{Kind: KeyRight}, nil
+	case 'D':
+		return Key{Kind: KeyLeft}, nil
+	default:
+		return Key{Kind: KeyUnknown}, nil
+	}
+}
+
+// parseSGRMouse parses the parameters "button;x;y" of an xterm SGR mouse report,
+// the report ends with "M" for a press and "m" for a release.
+func parseSGRMouse(params string, pressed bool) Key {
+	fields := strings.Split(params, ";")
+	if len(fields) != 3 {
+		return Key{Kind: KeyUnknown}
+	}
+
+	var values [3]int
+
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return Key{Kind: KeyUnknown}
+		}
+
+		values[i] = v
+	}
+
+	return Key{Kind: KeyMouse, Mouse: MouseEvent{
+		Button:  mouseButton(values[0]),
+		X:       values[1],
+		Y:       values[2],
+		Pressed: pressed,
+	}}
+}
+
+// readLegacyMouse reads the X10 mouse report that follows "ESC [ M": three bytes
+// with the button, column and line increased by 32. It's sent by terminals without SGR support.
+func readLegacyMouse(r *bufio.Reader) (Key, error) {
+	var report [3]byte
+
+	for i := range report {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+
+		report[i] = b - 32
+	}
+
+	button := mouseButton(int(report[0]))
+
+	return Key{Kind: KeyMouse, Mouse: MouseEvent{
+		Button:  button,
+		X:       int(report[1]),
+		Y:       int(report[2]),
+		Pressed: button != MouseRelease,
+	}}, nil
+}
+
+// mouseButton decodes the button code of a mouse report, modifier keys are ignored.
+func mouseButton(code int) MouseButton {
+	const (
+		modifiers = 4 | 8 | 16
+		motion    = 32
+		wheel     = 64
+	)
+
+	if code&motion != 0 {
+		return MouseOther
+	}
+
+	code &^= modifiers
+
+	switch code {
+	case 0:
+		return MouseLeft
+	case 1:
+		return MouseMiddle
+	case 2:
+		return MouseRight
+	case 3:
+		return MouseRelease
+	case wheel:
+		return MouseWheelUp
+	case wheel | 1:
+		return MouseWheelDown
+	default:
+		return MouseOther
+	}
+}
@@ -0,0 +1,392 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"strings"
+	"time"
+)
+
+const (
+	// chromeLines is the number of lines around the board: the status bar, column labels, message and help.
+	chromeLines = 4
+	// boardFirstLine is the 1-based terminal line of the first visible row, below the status bar and column labels.
+	boardFirstLine = 3
+	keysHelp       = "arrows/WASD/hjkl move, space open, f flag, c chord, u undo, r redo, ? hint, n new, : command, q quit; " +
+		"mouse: left open, right flag, middle or left+right chord"
+)
+
+// screen holds the state of the full-screen view: the cursor on the board, the viewport
+// that shows the part of the board around the cursor and the last message for the player.
+type screen struct {
+	dispatcher *command.Dispatcher
+	renderer   render.Terminal
+	notation   command.Notation
+	cursor     board.Position
+	// top and left are the first row and column shown in the viewport.
+	top    int
+	left   int
+	width  int
+	height int
+
+	message string
+	// commandLine holds the command typed after ":", editing is true while it's typed.
+	commandLine string
+	editing     bool
+	mouse       mouseState
+}
+
+// mouseState tracks the mouse buttons held down, so pressing left and right buttons together chords.
+// Nothing is done on release of the buttons that chorded.
+type mouseState struct {
+	left    bool
+	right   bool
+	chorded bool
+}
+
+func newScreen(d *command.Dispatcher, r render.Terminal) *screen {
+	return &screen{dispatcher: d, renderer: r, notation: r.Notation, width: 80, height: 24}
+}
+
+func (s *screen) game() *game.Game {
+	return s.dispatcher.Game()
+}
+
+// handleKey applies the key pressed by the player. Returns true if the player wants to leave.
+func (s *screen) handleKey(k Key) bool {
+	if s.editing {
+		s.editCommandLine(k)
+		return false
+	}
+
+	if k.Kind == KeyInterrupt {
+		return true
+	}
+
+	switch k.Kind {
+	case KeyUp:
+		s.moveCursor(-1, 0)
+	case KeyDown:
+		s.moveCursor(1, 0)
+	case KeyLeft:
+		s.moveCursor(0, -1)
+	case KeyRight:
+		s.moveCursor(0, 1)
+	case KeyEnter:
+		s.execute(command.Command{Kind: command.Open, Position: s.cursor})
+	case KeyRune:
+		return s.handleRune(k.Rune)
+	case KeyMouse:
+		s.handleMouse(k.Mouse)
+	}
+
+	return false
+}
+
+// handleMouse applies the mouse event: the left button opens the cell on release, the right button flags it
+// on release, the middle button or both left and right buttons chord on press. The wheel moves the cursor.
+func (s *screen) handleMouse(m MouseEvent) {
+	switch m.Button {
+	case MouseWheelUp:
+		s.moveCursor(-1, 0)
+		return
+	case MouseWheelDown:
+		s.moveCursor(1, 0)
+		return
+	case MouseOther:
+		return
+	}
+
+	pos, onBoard := s.cellAt(m.X, m.Y)
+	if onBoard {
+		s.cursor = pos
+	}
+
+	if m.Pressed {
+		switch m.Button {
+		case MouseLeft:
+			s.mouse.left = true
+		case MouseRight:
+			s.mouse.right = true
+		}
+
+		if onBoard && (m.Button == MouseMiddle || s.mouse.left && s.mouse.right) {
+			s.mouse.chorded = s.mouse.left && s.mouse.right
+			s.execute(command.Command{Kind: command.Chord, Position: pos})
+		}
+
+		return
+	}
+
+	releaseLeft := m.Button == MouseLeft || m.Button == MouseRelease
+	releaseRight := m.Button == MouseRight || m.Button == MouseRelease
+
+	if onBoard && !s.mouse.chorded {
+		switch {
+		case releaseLeft && s.mouse.left:
+			s.execute(command.Command{Kind: command.Open, Position: pos})
+		case releaseRight && s.mouse.right:
+			s.execute(command.Command{Kind: command.Flag, Position: pos})
+		}
+	}
+
+	if releaseLeft {
+		s.mouse.left = false
+	}
+
+	if releaseRight {
+		s.mouse.right = false
+	}
+
+	if !s.mouse.left && !s.mouse.right {
+		s.mouse.chorded = false
+	}
+}
+
+// cellAt returns the board cell drawn at the 1-based terminal column x and line y,
+// taking into account the labels and the viewport. Returns false if there is no cell there.
+func (s *screen) cellAt(x int, y int) (board.Position, bool) {
+	labelWidth, cellWidth, rows, cols := s.layout()
+
+	row := y - boardFirstLine
+	col := x - 1 - labelWidth
+
+	if row < 0 || row >= rows || col < 0 || col/cellWidth >= cols {
+		return board.Position{}, false
+	}
+
+	return board.Position{Row: s.top + row, Col: s.left + col/cellWidth}, true
+}
+
+func (s *screen) handleRune(r rune) bool {
+	switch r {
+	case 'w', 'W', 'k', 'K':
+		s.moveCursor(-1, 0)
+	case 's', 'S', 'j', 'J':
+		s.moveCursor(1, 0)
+	case 'a', 'A', 'h', 'H':
+		s.moveCursor(0, -1)
+	case 'd', 'D', 'l', 'L':
+		s.moveCursor(0, 1)
+	case ' ':
+		s.execute(command.Command{Kind: command.Open, Position: s.cursor})
+	case 'f', 'F':
+		s.execute(command.Command{Kind: command.Flag, Position: s.cursor})
+	case 'c', 'C':
+		s.execute(command.Command{Kind: command.Chord, Position: s.cursor})
+	case 'u', 'U':
+		s.execute(command.Command{Kind: command.Undo})
+	case 'r', 'R':
+		s.execute(command.Command{Kind: command.Redo})
+	case '?':
+		s.execute(command.Command{Kind: command.Hint})
+	case 'n', 'N':
+		s.execute(command.Command{Kind: command.New})
+	case ':':
+		s.editing = true
+		s.commandLine = ""
+	case 'q', 'Q':
+		return true
+	}
+
+	return false
+}
+
+func (s *screen) editCommandLine(k Key) {
+	switch k.Kind {
+	case KeyEnter:
+		s.editing = false
+		s.executeLine(s.commandLine)
+	case KeyEscape, KeyInterrupt:
+		s.editing = false
+	case KeyBackspace:
+		if n := len([]rune(s.commandLine)); n > 0 {
+			s.commandLine = string([]rune(s.commandLine)[:n-1])
+		}
+	case KeyRune:
+		s.commandLine += string(k.Rune)
+	}
+}
+
+func (s *screen) executeLine(line string) {
+	cmd, err := s.notation.Parse(line)
+	if err != nil {
+		s.message = err.Error()
+		return
+	}
+
+	s.execute(cmd)
+}
+
+func (s *screen) execute(cmd command.Command) {
+	result, err := s.dispatcher.Execute(cmd)
+
+	switch {
+	case errors.Is(err, game.ErrGameOver):
+		s.message = "The game is over: press u to undo the last move, n for a new game or q to quit."
+	case err != nil:
+		s.message = fmt.Sprintf("Failed to %s: %s", cmd.Kind, err)
+	case result.GameChanged:
+		s.cursor = board.Position{}
+		s.message = result.Message
+	default:
+		s.message = result.Message
+	}
+
+	if err == nil {
+		switch s.game().Status() {
+		case game.StatusWon:
+			s.message = "Great job, champion! Press n for a new game or q to quit."
+		case game.StatusLost:
+			s.message = "Oops! This time a Black Hole captured you! Press u to undo, n for a new game or q to quit."
+		}
+	}
+
+	s.clampCursor()
+}
+
+func (s *screen) moveCursor(dRow int, dCol int) {
+	s.cursor.Row += dRow
+	s.cursor.Col += dCol
+	s.clampCursor()
+}
+
+func (s *screen) clampCursor() {
+	cfg := s.game().Config()
+
+	s.cursor.Row = max(0, min(s.cursor.Row, cfg.NumRows-1))
+	s.cursor.Col = max(0, min(s.cursor.Col, cfg.NumCols-1))
+}
+
+// layout returns the width of row labels, the width of a cell and the number of rows and columns
+// that fit into the screen.
+func (s *screen) layout() (labelWidth int, cellWidth int, rows int, cols int) {
+	cfg := s.game().Config()
+
+	labelWidth = len(s.notation.RowLabel(cfg.NumRows-1)) + 1
+	cellWidth = max(3, len(command.ColumnLetters(cfg.NumCols-1))+1, s.renderer.Theme.Width+1)
+	rows = max(1, min(cfg.NumRows, s.height-chromeLines))
+	cols = max(1, min(cfg.NumCols, (s.width-labelWidth)/cellWidth))
+
+	return labelWidth, cellWidth, rows, cols
+}
+
+// scroll moves the viewport, so the cursor is visible.
+func (s *screen) scroll() {
+	_, _, rows, cols := s.layout()
+
+	s.top = scrollOffset(s.top, s.cursor.Row, rows)
+	s.left = scrollOffset(s.left, s.cursor.Col, cols)
+}
+
+// scrollOffset returns the first visible index of a viewport of the specified size that shows the cursor.
+func scrollOffset(offset int, cursor int, size int) int {
+	if cursor < offset {
+		return cursor
+	}
+
+	if cursor >= offset+size {
+		return cursor - size + 1
+	}
+
+	return offset
+}
+
+// render draws the screen: the status bar, the visible part of the board with labels,
+// the message for the player and the help line.
+func (s *screen) render(w io.Writer) {
+	s.scroll()
+
+	labelWidth, cellWidth, rows, cols := s.layout()
+	g := s.game()
+	snapshot := render.SnapshotOf(g)
+
+	var b strings.Builder
+
+	b.WriteString("\x1b[H")
+
+	s.writeLine(&b, s.statusBar(snapshot.State))
+
+	header := strings.Repeat(" ", labelWidth)
+	for j := s.left; j < s.left+cols; j++ {
+		header += fmt.Sprintf("%*s", cellWidth, command.ColumnLetters(j))
+	}
+
+	s.writeLine(&b, header)
+
+	for i := s.top; i < s.top+rows; i++ {
+		var line strings.Builder
+
+		line.WriteString(fmt.Sprintf("%*s ", labelWidth-1, s.notation.RowLabel(i)))
+
+		for j := s.left; j < s.left+cols; j++ {
+			cell := s.renderer.Cell(snapshot, i, j, cellWidth-1)
+
+			if s.cursor == (board.Position{Row: i, Col: j}) {
+				line.WriteString(" \x1b[7m" + cell + "\x1b[0m")
+			} else {
+				line.WriteString(" " + cell)
+			}
+		}
+
+		s.writeLine(&b, line.String())
+	}
+
+	if s.editing {
+		s.writeLine(&b, ":"+s.commandLine)
+	} else {
+		s.writeLine(&b, s.message)
+	}
+
+	b.WriteString(truncate(keysHelp, s.width) + "\x1b[K\x1b[J")
+
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeLine writes the line clearing the rest of it, the line is cut to the screen's width.
+func (s *screen) writeLine(b *strings.Builder, line string) {
+	if !strings.Contains(line, "\x1b") {
+		line = truncate(line, s.width)
+	}
+
+	b.WriteString(line + "\x1b[K\r\n")
+}
+
+// statusBar shows the number of black holes left (unflagged), the time and the position of the cursor.
+func (s *screen) statusBar(state [][]board.CellValue) string {
+	g := s.game()
+
+	flags := 0
+
+	for _, row := range state {
+		for _, v := range row {
+			if v == board.CellValueFlag {
+				flags++
+			}
+		}
+	}
+
+	elapsed := g.Elapsed().Round(time.Second)
+	status := g.Status().String()
+
+	if g.IsPaused() {
+		status = "paused"
+	}
+
+	return fmt.Sprintf("Black holes left: %d | Time: %02d:%02d | %s | Cell: %s%s (%s)",
+		g.NumBlackHoles()-flags, int(elapsed.Minutes()), int(elapsed.Seconds())%60, status,
+		command.ColumnLetters(s.cursor.Col), s.notation.RowLabel(s.cursor.Row), s.notation.FormatPosition(s.cursor))
+}
+
+func truncate(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:max(0, width)])
+	}
+
+	return s
+}
@@ -0,0 +1,335 @@
+package tui
+
+import (
+	"bufio"
+	"io"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\x1b[A\x1b[B\x1bOC\x1b[D\r\x7f\x03\x1b[1;5Hé\x1b"))
+
+	expected := []Key{
+		{Kind: KeyRune, Rune: 'a'},
+		{Kind: KeyUp},
+		{Kind: KeyDown},
+		{Kind: KeyRight},
+		{Kind: KeyLeft},
+		{Kind: KeyEnter},
+		{Kind: KeyBackspace},
+		{Kind: KeyInterrupt},
+		{Kind: KeyUnknown},
+		{Kind: KeyRune, Rune: 'é'},
+		{Kind: KeyEscape},
+	}
+
+	for _, e := range expected {
+		k, err := readKey(r)
+		require.NoError(t, err)
+		assert.Equal(t, e, k)
+	}
+
+	_, err := readKey(r)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// newTestScreen creates a screen for the 3x3 game with black holes at (1,2) and (2,2):
+//
+//	0 1 1
+//	0 2 H
+//	0 2 H
+func newTestScreen(t *testing.T) *screen {
+	t.Helper()
+
+	newGame := func() (*game.Game, error) {
+		return game.NewGame(game.Config{
+			NumRows:          3,
+			NumCols:          3,
+			NumBlackHoles:    2,
+			BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+		})
+	}
+
+	g, err := newGame()
+	require.NoError(t, err)
+
+	return newScreen(command.NewDispatcher(g, command.Config{NewGame: newGame}), render.Terminal{Theme: render.ASCII})
+}
+
+func press(s *screen, keys string) bool {
+	for _, r := range keys {
+		if s.handleKey(Key{Kind: KeyRune, Rune: r}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestScreen_HandleKey(t *testing.T) {
+	t.Run("Cursor stays on the board", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		press(s, "wakh")
+		assert.Equal(t, board.Position{}, s.cursor)
+
+		press(s, "sjdlsjdl")
+		assert.Equal(t, board.Position{Row: 2, Col: 2}, s.cursor)
+
+		s.handleKey(Key{Kind: KeyUp})
+		s.handleKey(Key{Kind: KeyLeft})
+		assert.Equal(t, board.Position{Row: 1, Col: 1}, s.cursor)
+	})
+
+	t.Run("Play to the win", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		// open (0,2), flag (1,2), chord (0,2), undo and redo the chord, open (2,0)
+		press(s, "dd d jf kc ur jjaa ")
+
+		assert.True(t, s.game().IsWon())
+		assert.Contains(t, s.message, "Great job")
+	})
+
+	t.Run("Lose, undo and start a new game", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		press(s, "ddjj ")
+		require.True(t, s.game().IsOver())
+		assert.Contains(t, s.message, "Black Hole")
+
+		press(s, " ")
+		assert.Contains(t, s.message, "The game is over")
+
+		press(s, "u")
+		assert.False(t, s.game().IsOver())
+
+		press(s, " n")
+		assert.Empty(t, s.game().History())
+		assert.Equal(t, board.Position{}, s.cursor)
+	})
+
+	t.Run("Command line", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		press(s, ":o C1")
+		s.handleKey(Key{Kind: KeyBackspace})
+		press(s, "2")
+		assert.Equal(t, "o C2", s.commandLine)
+		assert.False(t, press(s, "q"), "q is typed into the command line")
+
+		s.handleKey(Key{Kind: KeyBackspace})
+		s.handleKey(Key{Kind: KeyEnter})
+		assert.False(t, s.editing)
+		assert.True(t, s.game().IsOver())
+
+		press(s, ":dance")
+		s.handleKey(Key{Kind: KeyEnter})
+		assert.Contains(t, s.message, "unknown command")
+	})
+
+	t.Run("Quit", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, press(newTestScreen(t), "q"))
+		assert.True(t, newTestScreen(t).handleKey(Key{Kind: KeyInterrupt}))
+	})
+}
+
+func TestScreen_Render(t *testing.T) {
+	t.Run("Board with labels and status bar", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+		press(s, "f")
+
+		var b strings.Builder
+		s.render(&b)
+
+		assert.Contains(t, b.String(), "Black holes left: 1 | Time: 00:00 | in progress | Cell: A1 (1,1)")
+		assert.Contains(t, b.String(), "\r\n    A  B  C\x1b[K\r\n")
+		assert.Contains(t, b.String(), "\r\n1  \x1b[7m F\x1b[0m  ?  ?\x1b[K\r\n")
+	})
+
+	t.Run("Viewport follows the cursor", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{NumRows: 30, NumCols: 60, NumBlackHoles: 1,
+			BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(1)})
+		require.NoError(t, err)
+
+		s := newScreen(command.NewDispatcher(g, command.Config{}), render.Terminal{Theme: render.ASCII})
+		s.width, s.height = 40, 10
+
+		s.cursor = board.Position{Row: 29, Col: 59}
+
+		var b strings.Builder
+		s.render(&b)
+
+		_, _, rows, cols := s.layout()
+		assert.Equal(t, 6, rows)
+		assert.Equal(t, 12, cols)
+		assert.Equal(t, 24, s.top)
+		assert.Equal(t, 48, s.left)
+		assert.Contains(t, b.String(), "BH")
+		assert.Contains(t, b.String(), "\r\n30 ")
+		assert.NotContains(t, b.String(), "\r\n24 ")
+
+		s.cursor = board.Position{}
+		s.render(&b)
+		assert.Equal(t, 0, s.top)
+		assert.Equal(t, 0, s.left)
+	})
+}
+
+func TestReadKey_Mouse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       string
+		expected MouseEvent
+	}{
+		{name: "SGR left press", in: "\x1b[<0;12;5M", expected: MouseEvent{Button: MouseLeft, X: 12, Y: 5, Pressed: true}},
+		{name: "SGR right release", in: "\x1b[<2;12;5m", expected: MouseEvent{Button: MouseRight, X: 12, Y: 5}},
+		{name: "SGR middle press with Ctrl", in: "\x1b[<17;1;1M", expected: MouseEvent{Button: MouseMiddle, X: 1, Y: 1, Pressed: true}},
+		{name: "SGR wheel", in: "\x1b[<65;3;4M", expected: MouseEvent{Button: MouseWheelDown, X: 3, Y: 4, Pressed: true}},
+		{name: "SGR motion", in: "\x1b[<32;3;4M", expected: MouseEvent{Button: MouseOther, X: 3, Y: 4, Pressed: true}},
+		{name: "Legacy press", in: "\x1b[M ,%", expected: MouseEvent{Button: MouseLeft, X: 12, Y: 5, Pressed: true}},
+		{name: "Legacy release", in: "\x1b[M#,%", expected: MouseEvent{Button: MouseRelease, X: 12, Y: 5}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := bufio.NewReader(strings.NewReader(tc.in + "x"))
+
+			k, err := readKey(r)
+			require.NoError(t, err)
+			assert.Equal(t, Key{Kind: KeyMouse, Mouse: tc.expected}, k)
+
+			// the whole report is consumed
+			k, err = readKey(r)
+			require.NoError(t, err)
+			assert.Equal(t, Key{Kind: KeyRune, Rune: 'x'}, k)
+		})
+	}
+}
+
+// click returns the mouse events of a click at the center of the cell on the test screen.
+// The row labels take 2 columns and every cell takes 3, the first row is drawn on the line 3.
+func click(button MouseButton, row int, col int) []Key {
+	x, y := 2+col*3+3, 3+row
+
+	return []Key{
+		{Kind: KeyMouse, Mouse: MouseEvent{Button: button, X: x, Y: y, Pressed: true}},
+		{Kind: KeyMouse, Mouse: MouseEvent{Button: button, X: x, Y: y}},
+	}
+}
+
+func handleKeys(s *screen, keys ...[]Key) {
+	for _, ks := range keys {
+		for _, k := range ks {
+			s.handleKey(k)
+		}
+	}
+}
+
+func TestScreen_HandleMouse(t *testing.T) {
+	t.Run("Open, flag and chord", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		handleKeys(s, click(MouseLeft, 0, 2), click(MouseRight, 1, 2))
+		assert.EqualValues(t, "1", s.game().BoardState()[0][2])
+		assert.EqualValues(t, board.CellValueFlag, s.game().BoardState()[1][2])
+		assert.Equal(t, board.Position{Row: 1, Col: 2}, s.cursor)
+
+		handleKeys(s, click(MouseMiddle, 0, 2))
+		assert.EqualValues(t, "2", s.game().BoardState()[1][1])
+	})
+
+	t.Run("Left and right buttons together chord", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		handleKeys(s, click(MouseLeft, 0, 2), click(MouseRight, 1, 2))
+
+		left, right := click(MouseLeft, 0, 2), click(MouseRight, 0, 2)
+		handleKeys(s, []Key{left[0], right[0], left[1], right[1]})
+
+		assert.EqualValues(t, "2", s.game().BoardState()[1][1])
+		assert.EqualValues(t, board.CellValueFlag, s.game().BoardState()[1][2], "the flag stays")
+		assert.Len(t, s.game().History(), 3)
+	})
+
+	t.Run("Legacy release opens the cell", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		keys := click(MouseLeft, 0, 0)
+		keys[1].Mouse.Button = MouseRelease
+		handleKeys(s, keys)
+
+		assert.EqualValues(t, "0", s.game().BoardState()[0][0])
+	})
+
+	t.Run("Clicks outside the board are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		handleKeys(s, click(MouseLeft, 5, 0), click(MouseLeft, 0, 5), click(MouseLeft, -1, 0),
+			[]Key{{Kind: KeyMouse, Mouse: MouseEvent{Button: MouseLeft, X: 1, Y: 3, Pressed: true}}})
+
+		assert.Empty(t, s.game().History())
+	})
+
+	t.Run("Coordinates take the viewport into account", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{NumRows: 30, NumCols: 60, NumBlackHoles: 1,
+			BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 0, Col: 0}})})
+		require.NoError(t, err)
+
+		s := newScreen(command.NewDispatcher(g, command.Config{}), render.Terminal{Theme: render.ASCII})
+		s.width, s.height = 40, 10
+		s.cursor = board.Position{Row: 29, Col: 59}
+		s.render(io.Discard)
+
+		// the row labels take 3 columns for the 2-digit rows
+		s.handleKey(Key{Kind: KeyMouse, Mouse: MouseEvent{Button: MouseRight, X: 3 + 3, Y: 3, Pressed: true}})
+		s.handleKey(Key{Kind: KeyMouse, Mouse: MouseEvent{Button: MouseRight, X: 3 + 3, Y: 3}})
+
+		assert.Equal(t, board.Position{Row: 24, Col: 48}, s.cursor)
+		assert.EqualValues(t, board.CellValueFlag, g.BoardState()[24][48])
+	})
+
+	t.Run("Wheel moves the cursor", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestScreen(t)
+
+		s.handleKey(Key{Kind: KeyMouse, Mouse: MouseEvent{Button: MouseWheelDown, Pressed: true}})
+		assert.Equal(t, board.Position{Row: 1}, s.cursor)
+	})
+}
@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || windows)
+
+package tui
+
+import (
+	"errors"
+	"os"
+)
+
+// terminal is a stub for platforms without raw mode support.
+type terminal struct{}
+
+func makeRaw(*os.File, *os.File) (*terminal, error) {
+	return nil, errors.New("the full-screen mode isn't supported on this platform")
+}
+
+func (t *terminal) restore() error {
+	return nil
+}
+
+func (t *terminal) size() (int, int, error) {
+	return 0, 0, errors.New("the full-screen mode isn't supported on this platform")
+}
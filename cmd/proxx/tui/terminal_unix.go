@@ -0,0 +1,71 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package tui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminal keeps the original settings of the terminal to restore them on exit.
+type terminal struct {
+	in       *os.File
+	out      *os.File
+	original syscall.Termios
+}
+
+// makeRaw puts the terminal into raw mode: input is available byte by byte without echo,
+// Ctrl-C arrives as a key instead of a signal and the output isn't post-processed.
+func makeRaw(in *os.File, out *os.File) (*terminal, error) {
+	t := &terminal{in: in, out: out}
+
+	if err := ioctl(in.Fd(), ioctlGetTermios, unsafe.Pointer(&t.original)); err != nil {
+		return nil, err
+	}
+
+	raw := t.original
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := ioctl(in.Fd(), ioctlSetTermios, unsafe.Pointer(&raw)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// restore brings back the original settings of the terminal.
+func (t *terminal) restore() error {
+	return ioctl(t.in.Fd(), ioctlSetTermios, unsafe.Pointer(&t.original))
+}
+
+// size returns the number of columns and lines of the terminal.
+func (t *terminal) size() (int, int, error) {
+	var ws struct {
+		Row    uint16
+		Col    uint16
+		Xpixel uint16
+		Ypixel uint16
+	}
+
+	if err := ioctl(t.out.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0, err
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func ioctl(fd uintptr, request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg)); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
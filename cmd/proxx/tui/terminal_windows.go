@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	enableProcessedInput            = 0x0001
+	enableLineInput                 = 0x0002
+	enableEchoInput                 = 0x0004
+	enableVirtualTerminalInput      = 0x0200
+	enableProcessedOutput           = 0x0001
+	enableVirtualTerminalProcessing = 0x0004
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// terminal keeps the original modes of the console to restore them on exit.
+type terminal struct {
+	in      *os.File
+	out     *os.File
+	inMode  uint32
+	outMode uint32
+}
+
+// makeRaw puts the console into raw mode: input is available key by key without echo,
+// keys arrive as ANSI escape sequences and the output understands them.
+func makeRaw(in *os.File, out *os.File) (*terminal, error) {
+	t := &terminal{in: in, out: out}
+
+	if err := syscall.GetConsoleMode(syscall.Handle(in.Fd()), &t.inMode); err != nil {
+		return nil, err
+	}
+
+	if err := syscall.GetConsoleMode(syscall.Handle(out.Fd()), &t.outMode); err != nil {
+		return nil, err
+	}
+
+	inMode := t.inMode&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if err := setConsoleMode(in, inMode); err != nil {
+		return nil, err
+	}
+
+	if err := setConsoleMode(out, t.outMode|enableProcessedOutput|enableVirtualTerminalProcessing); err != nil {
+		_ = setConsoleMode(in, t.inMode)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// restore brings back the original modes of the console.
+func (t *terminal) restore() error {
+	if err := setConsoleMode(t.in, t.inMode); err != nil {
+		return err
+	}
+
+	return setConsoleMode(t.out, t.outMode)
+}
+
+// size returns the number of columns and lines of the console window.
+func (t *terminal) size() (int, int, error) {
+	var info struct {
+		Size              [2]int16
+		CursorPosition    [2]int16
+		Attributes        uint16
+		Window            [4]int16
+		MaximumWindowSize [2]int16
+	}
+
+	r, _, err := procGetConsoleScreenBufferInfo.Call(t.out.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0, 0, err
+	}
+
+	return int(info.Window[2]-info.Window[0]) + 1, int(info.Window[3]-info.Window[1]) + 1, nil
+}
+
+func setConsoleMode(f *os.File, mode uint32) error {
+	r, _, err := procSetConsoleMode.Call(f.Fd(), uintptr(mode))
+	if r == 0 {
+		return err
+	}
+
+	return nil
+}
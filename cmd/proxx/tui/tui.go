@@ -0,0 +1,101 @@
+// Package tui implements the full-screen terminal mode of the game: the board is drawn with ANSI escapes
+// and the player moves a cursor over it with the keyboard.
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"syscall"
+	"time"
+)
+
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAlternateScreen = "\x1b[0m\x1b[?25h\x1b[?1049l"
+	// enableMouse asks for reports of mouse buttons in the SGR encoding, terminals that don't support it ignore
+	// the request and the game is played with the keyboard only.
+	enableMouse  = "\x1b[?1000h\x1b[?1006h"
+	disableMouse = "\x1b[?1006l\x1b[?1000l"
+)
+
+// Run plays the game in the full-screen mode on the terminal until the player quits.
+// The commands are executed by the dispatcher created with cfg, so the player can start new games,
+// save and load them. The terminal is restored on exit, on a panic and when the process is interrupted.
+// Cells are drawn by the renderer, positions are written in its notation. Returns the game being played at the end.
+func Run(g *game.Game, cfg command.Config, r render.Terminal, in *os.File, out *os.File) (*game.Game, error) {
+	term, err := makeRaw(in, out)
+	if err != nil {
+		return g, fmt.Errorf("failed to switch the terminal into raw mode: %w", err)
+	}
+
+	// deferred calls run on panics as well
+	defer func() { _ = term.restore() }()
+
+	if _, err := io.WriteString(out, enterAlternateScreen+enableMouse); err != nil {
+		return g, err
+	}
+	defer func() { _, _ = io.WriteString(out, disableMouse+leaveAlternateScreen) }()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	keys := make(chan Key)
+	keyErrs := make(chan error, 1)
+
+	go readKeys(bufio.NewReader(in), keys, keyErrs)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	s := newScreen(command.NewDispatcher(g, cfg), r)
+	w := bufio.NewWriter(out)
+
+	for {
+		if width, height, err := term.size(); err == nil && width > 0 && height > 0 {
+			s.width, s.height = width, height
+		}
+
+		s.render(w)
+
+		if err := w.Flush(); err != nil {
+			return s.game(), err
+		}
+
+		select {
+		case k := <-keys:
+			if s.handleKey(k) {
+				return s.game(), nil
+			}
+		case err := <-keyErrs:
+			if errors.Is(err, io.EOF) {
+				return s.game(), nil
+			}
+
+			return s.game(), err
+		case <-signals:
+			return s.game(), nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readKeys reads keys from the terminal until an error happens.
+func readKeys(r *bufio.Reader, keys chan<- Key, errs chan<- error) {
+	for {
+		k, err := readKey(r)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		keys <- k
+	}
+}
@@ -0,0 +1,145 @@
+// Package api holds the documents shared by the front ends that play games over JSON, the HTTP server
+// and the engine: the request to create a game, the view of a game and its events.
+package api
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"time"
+)
+
+// DefaultMaxCells limits the size of a board, so a single request can't exhaust the memory.
+const DefaultMaxCells = 10000
+
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidConfig  = errors.New("invalid game configuration")
+)
+
+// CreateRequest describes the board of a new game: either a preset or rows, columns and the number
+// or density of black holes. A seed gives the same board every time.
+type CreateRequest struct {
+	Preset         string  `json:"preset"`
+	Rows           int     `json:"rows"`
+	Cols           int     `json:"cols"`
+	Holes          int     `json:"holes"`
+	Density        float64 `json:"density"`
+	Seed           *int64  `json:"seed"`
+	Locator        string  `json:"locator"`
+	FirstClickSafe bool    `json:"first_click_safe"`
+}
+
+// Config returns the configuration of the game described by the request. The presets are available
+// in addition to the built-in ones, a board can't have more than maxCells cells.
+func (r CreateRequest) Config(presets []game.Preset, maxCells int) (game.Config, error) {
+	var cfg game.Config
+
+	if r.Preset != "" {
+		if r.Rows != 0 || r.Cols != 0 || r.Holes != 0 || r.Density != 0 {
+			return game.Config{}, fmt.Errorf("%w: a preset can't be combined with rows, cols, holes and density",
+				ErrInvalidRequest)
+		}
+
+		preset, err := game.PresetByName(r.Preset, presets...)
+		if err != nil {
+			return game.Config{}, err
+		}
+
+		cfg = preset.Config(nil)
+	} else {
+		cfg = game.Config{NumRows: r.Rows, NumCols: r.Cols, NumBlackHoles: r.Holes, BlackHoleDensity: r.Density}
+	}
+
+	if cfg.NumRows > maxCells || cfg.NumCols > maxCells || cfg.NumRows*cfg.NumCols > maxCells {
+		return game.Config{}, fmt.Errorf("%w: the board can't have more than %d cells", ErrInvalidConfig, maxCells)
+	}
+
+	seed := time.Now().UnixNano()
+	if r.Seed != nil {
+		seed = *r.Seed
+	}
+
+	name := r.Locator
+	if name == "" {
+		name = game.UniformLocatorName
+	}
+
+	locator, err := game.NewBlackHoleLocator(name, seed)
+	if err != nil {
+		return game.Config{}, err
+	}
+
+	cfg.BlackHoleLocator = locator
+	cfg.FirstClickSafe = r.FirstClickSafe
+
+	if err := cfg.Validate(); err != nil {
+		return game.Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return cfg, nil
+}
+
+// Game is what the player sees of a game. It's built only from the public state of the board,
+// so closed cells never leak.
+type Game struct {
+	Rows       int                 `json:"rows"`
+	Cols       int                 `json:"cols"`
+	BlackHoles int                 `json:"black_holes"`
+	Flags      int                 `json:"flags"`
+	Status     string              `json:"status"`
+	Resigned   bool                `json:"resigned,omitempty"`
+	ElapsedMs  int64               `json:"elapsed_ms"`
+	Exploded   *[2]int             `json:"exploded,omitempty"`
+	Cells      [][]board.CellValue `json:"cells"`
+}
+
+// Cell is the value of a cell the player sees, positions are zero-based.
+type Cell struct {
+	Row   int             `json:"row"`
+	Col   int             `json:"col"`
+	Value board.CellValue `json:"value"`
+}
+
+// NewGame returns the view of the game.
+func NewGame(g *game.Game) Game {
+	v := Game{
+		Rows:       g.Config().NumRows,
+		Cols:       g.Config().NumCols,
+		BlackHoles: g.NumBlackHoles(),
+		Status:     g.Status().String(),
+		Resigned:   g.IsResigned(),
+		ElapsedMs:  elapsedMs(g),
+		Cells:      g.BoardState(),
+	}
+
+	for _, row := range v.Cells {
+		for _, c := range row {
+			if c == board.CellValueFlag {
+				v.Flags++
+			}
+		}
+	}
+
+	if pos, ok := g.LostAt(); ok {
+		v.Exploded = &[2]int{pos.Row, pos.Col}
+	}
+
+	return v
+}
+
+// Revealed returns the cells opened by a move.
+func Revealed(r game.OpenResult) []Cell {
+	cells := make([]Cell, 0, len(r.Revealed))
+
+	for _, c := range r.Revealed {
+		cells = append(cells, Cell{Row: c.Position.Row, Col: c.Position.Col, Value: c.Value})
+	}
+
+	return cells
+}
+
+func elapsedMs(g *game.Game) int64 {
+	return g.Elapsed().Round(time.Millisecond).Milliseconds()
+}
@@ -0,0 +1,64 @@
+package api
+
+import "proxx/internal/proxx/game"
+
+// Types of the events.
+const (
+	EventGameStarted  = "game_started"
+	EventCellsOpened  = "cells_opened"
+	EventCellFlagged  = "cell_flagged"
+	EventGameWon      = "game_won"
+	EventGameLost     = "game_lost"
+	EventGameResigned = "game_resigned"
+	EventMoveUndone   = "move_undone"
+)
+
+// Event is a change of a game the player can see. Cells hold the values of the changed cells,
+// an undone move changes the whole board, so Game holds the new view of the game then.
+// Every front end names the fields in its own protocol.
+type Event struct {
+	Type      string
+	Cells     []Cell
+	Exploded  *[2]int
+	Game      *Game
+	ElapsedMs int64
+}
+
+// NewEvent returns the event the player sees, the values of the cells are read from the board.
+// Clock events aren't sent.
+func NewEvent(g *game.Game, e game.Event) (Event, bool) {
+	ev := Event{ElapsedMs: elapsedMs(g)}
+
+	switch e := e.(type) {
+	case game.GameStarted:
+		ev.Type = EventGameStarted
+	case game.CellOpened:
+		state := g.BoardState()
+
+		ev.Type = EventCellsOpened
+		ev.Cells = make([]Cell, 0, len(e.Positions))
+
+		for _, p := range e.Positions {
+			ev.Cells = append(ev.Cells, Cell{Row: p.Row, Col: p.Col, Value: state[p.Row][p.Col]})
+		}
+	case game.CellFlagged:
+		ev.Type = EventCellFlagged
+		ev.Cells = []Cell{{Row: e.Position.Row, Col: e.Position.Col,
+			Value: g.BoardState()[e.Position.Row][e.Position.Col]}}
+	case game.GameWon:
+		ev.Type = EventGameWon
+	case game.GameLost:
+		ev.Type = EventGameLost
+		ev.Exploded = &[2]int{e.Position.Row, e.Position.Col}
+	case game.GameResigned:
+		ev.Type = EventGameResigned
+	case game.MoveUndone:
+		view := NewGame(g)
+		ev.Type = EventMoveUndone
+		ev.Game = &view
+	default:
+		return Event{}, false
+	}
+
+	return ev, true
+}
@@ -0,0 +1,355 @@
+// Package arena benchmarks bots written in any language. A bot is an executable that plays games
+// through a line-based text protocol on its standard input and output, similar to UCI in chess.
+//
+// The arena writes commands, the bot answers some of them (positions are zero-based):
+//
+//	proxx                          the bot answers "id name <name>" (optional) and then "proxxok"
+//	newgame <rows> <cols> <holes>  a new game starts
+//	board <row> <row> ...          the visible board, every row is a string of cells:
+//	                               ? hidden, F flagged, 0-8 the number of black holes around
+//	go                             the bot answers "move open|flag|chord <row> <col>" within the move time
+//	result <outcome>               the game is over: won, lost, timeout, crash or illegal
+//	quit                           the bot should exit
+//
+// Lines starting with "info" are ignored, so bots may tell what they think.
+// A bot that crashes or hangs loses the game and is started again for the next one,
+// a bot that makes an illegal move loses the game as well.
+package arena
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome represents how a game of a bot ended.
+type Outcome int
+
+const (
+	OutcomeWon Outcome = iota
+	OutcomeLost
+	// OutcomeTimeout means the bot didn't move within the move time.
+	OutcomeTimeout
+	// OutcomeCrash means the bot exited or didn't read its input anymore.
+	OutcomeCrash
+	// OutcomeIllegal means the bot sent something that isn't a move or a move the game doesn't allow.
+	OutcomeIllegal
+)
+
+// Outcomes lists all the outcomes in the order of their values.
+var Outcomes = []Outcome{OutcomeWon, OutcomeLost, OutcomeTimeout, OutcomeCrash, OutcomeIllegal}
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeWon:
+		return "won"
+	case OutcomeLost:
+		return "lost"
+	case OutcomeTimeout:
+		return "timeout"
+	case OutcomeCrash:
+		return "crash"
+	case OutcomeIllegal:
+		return "illegal"
+	default:
+		return "unknown"
+	}
+}
+
+// Config represents the configuration of a benchmark.
+// Game i (counting from 0) is created by GameConfig with the seed Seed+i, so every bot run with the same seed
+// gets the same boards. MoveTime limits the time a bot may think about a single move.
+// Stderr receives the standard error of the bot, it's discarded if Stderr is nil.
+type Config struct {
+	Command    []string
+	Games      int
+	Seed       int64
+	GameConfig func(seed int64) (game.Config, error)
+	MoveTime   time.Duration
+	Stderr     io.Writer
+}
+
+// GameResult represents a game played by a bot.
+// Progress is the share of the cells free from black holes the bot opened, from 0 to 1.
+// Time is the time the bot spent thinking. Err explains the outcomes other than won and lost.
+type GameResult struct {
+	Seed     int64
+	Outcome  Outcome
+	Moves    int
+	Progress float64
+	Time     time.Duration
+	Err      error
+}
+
+// Report represents the results of a bot. Bot is the name the bot told or its executable.
+type Report struct {
+	Bot   string
+	Games []GameResult
+}
+
+// Count returns the number of games with the outcome.
+func (r Report) Count(o Outcome) int {
+	n := 0
+
+	for _, g := range r.Games {
+		if g.Outcome == o {
+			n++
+		}
+	}
+
+	return n
+}
+
+// WinRate returns the share of the games won, from 0 to 1.
+func (r Report) WinRate() float64 {
+	if len(r.Games) == 0 {
+		return 0
+	}
+
+	return float64(r.Count(OutcomeWon)) / float64(len(r.Games))
+}
+
+// AverageProgress returns the average progress of the games, from 0 to 1.
+func (r Report) AverageProgress() float64 {
+	if len(r.Games) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, g := range r.Games {
+		sum += g.Progress
+	}
+
+	return sum / float64(len(r.Games))
+}
+
+// Time returns the total time the bot spent thinking.
+func (r Report) Time() time.Duration {
+	var sum time.Duration
+	for _, g := range r.Games {
+		sum += g.Time
+	}
+
+	return sum
+}
+
+// AverageMoveTime returns the average time the bot spent on a move.
+func (r Report) AverageMoveTime() time.Duration {
+	moves := 0
+	for _, g := range r.Games {
+		moves += g.Moves
+	}
+
+	if moves == 0 {
+		return 0
+	}
+
+	return r.Time() / time.Duration(moves)
+}
+
+// Run plays the games with the bot. The bot is started before the first game and again after it crashed or hung.
+// Returns an error if the bot can't be started at all or a game can't be created.
+func Run(cfg Config) (Report, error) {
+	if len(cfg.Command) == 0 {
+		return Report{}, errors.New("the command of the bot is empty")
+	}
+
+	report := Report{Bot: cfg.Command[0]}
+
+	var b *bot
+
+	defer func() {
+		if b != nil {
+			b.stop()
+		}
+	}()
+
+	for i := 0; i < cfg.Games; i++ {
+		seed := cfg.Seed + int64(i)
+
+		gameCfg, err := cfg.GameConfig(seed)
+		if err != nil {
+			return report, err
+		}
+
+		g, err := game.NewGame(gameCfg)
+		if err != nil {
+			return report, err
+		}
+
+		if b == nil {
+			if b, err = startBot(cfg.Command, cfg.Stderr); err != nil && !isBotFailure(err) {
+				return report, fmt.Errorf("failed to start the bot: %w", err)
+			}
+		}
+
+		var result GameResult
+
+		if b != nil {
+			report.Bot = b.name
+			result = play(b, g, cfg.MoveTime)
+		} else {
+			result.Err = err
+		}
+
+		result.Seed = seed
+
+		if result.Err != nil {
+			result.Outcome = failureOutcome(result.Err)
+		} else if g.IsWon() {
+			result.Outcome = OutcomeWon
+		} else {
+			result.Outcome = OutcomeLost
+		}
+
+		result.Progress = progress(g)
+
+		switch result.Outcome {
+		case OutcomeTimeout, OutcomeCrash:
+			if b != nil {
+				b.kill()
+				b = nil
+			}
+		default:
+			if err := b.send("result %s", result.Outcome); err != nil {
+				b.kill()
+				b = nil
+			}
+		}
+
+		report.Games = append(report.Games, result)
+	}
+
+	return report, nil
+}
+
+// play asks the bot for moves until the game is over. The game ends early if the bot fails,
+// the error of the result tells why.
+func play(b *bot, g *game.Game, moveTime time.Duration) GameResult {
+	var result GameResult
+
+	rows, cols := g.Config().NumRows, g.Config().NumCols
+	// flags can be toggled forever, so the number of moves is limited
+	maxMoves := 4 * rows * cols
+
+	if result.Err = b.send("newgame %d %d %d", rows, cols, g.NumBlackHoles()); result.Err != nil {
+		return result
+	}
+
+	for !g.IsOver() {
+		if result.Moves >= maxMoves {
+			result.Err = fmt.Errorf("%w: more than %d moves", ErrIllegalMove, maxMoves)
+			return result
+		}
+
+		if result.Err = b.send("board %s", formatBoard(g.BoardState())); result.Err != nil {
+			return result
+		}
+
+		if result.Err = b.send("go"); result.Err != nil {
+			return result
+		}
+
+		start := time.Now()
+		line, err := b.receive(moveTime)
+		result.Time += time.Since(start)
+
+		if err != nil {
+			result.Err = err
+			return result
+		}
+
+		m, err := ParseMove(line)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+
+		result.Moves++
+
+		if err := g.Apply(m); err != nil {
+			result.Err = fmt.Errorf("%w: %q: %w", ErrIllegalMove, line, err)
+			return result
+		}
+	}
+
+	return result
+}
+
+// ParseMove parses the answer of a bot, e.g. "move open 0 1".
+func ParseMove(line string) (game.Move, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 || fields[0] != "move" {
+		return game.Move{}, fmt.Errorf("%w: %q isn't a move", ErrIllegalMove, line)
+	}
+
+	kind, err := game.ParseMoveKind(fields[1])
+	if err != nil {
+		return game.Move{}, fmt.Errorf("%w: %w", ErrIllegalMove, err)
+	}
+
+	row, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return game.Move{}, fmt.Errorf("%w: invalid row %q", ErrIllegalMove, fields[2])
+	}
+
+	col, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return game.Move{}, fmt.Errorf("%w: invalid column %q", ErrIllegalMove, fields[3])
+	}
+
+	return game.Move{Kind: kind, Position: board.Position{Row: row, Col: col}}, nil
+}
+
+// formatBoard returns the rows of the board separated by spaces.
+func formatBoard(state [][]board.CellValue) string {
+	rows := make([]string, len(state))
+
+	for i, row := range state {
+		var sb strings.Builder
+		for _, v := range row {
+			sb.WriteString(string(v))
+		}
+
+		rows[i] = sb.String()
+	}
+
+	return strings.Join(rows, " ")
+}
+
+// progress returns the share of the cells free from black holes that are open.
+func progress(g *game.Game) float64 {
+	opened := 0
+
+	for _, row := range g.BoardState() {
+		for _, v := range row {
+			if _, ok := v.Clue(); ok {
+				opened++
+			}
+		}
+	}
+
+	cfg := g.Config()
+
+	return float64(opened) / float64(cfg.NumRows*cfg.NumCols-g.NumBlackHoles())
+}
+
+func isBotFailure(err error) bool {
+	return errors.Is(err, ErrBotTimeout) || errors.Is(err, ErrBotExited)
+}
+
+func failureOutcome(err error) Outcome {
+	switch {
+	case errors.Is(err, ErrBotTimeout):
+		return OutcomeTimeout
+	case errors.Is(err, ErrBotExited):
+		return OutcomeCrash
+	default:
+		return OutcomeIllegal
+	}
+}
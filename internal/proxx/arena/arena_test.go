@@ -0,0 +1,210 @@
+package arena_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"proxx/internal/proxx/arena"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/solver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// botEnv makes the test binary run a bot with the behavior named by the variable instead of the tests.
+const botEnv = "PROXX_TEST_BOT"
+
+func TestMain(m *testing.M) {
+	if behavior := os.Getenv(botEnv); behavior != "" {
+		runBot(behavior)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runBot plays with the solver until it's stuck, then it opens the first hidden cell.
+// The behavior makes the bot fail on every move: "crash" exits, "hang" sleeps, "illegal" opens a cell outside
+// the board and "chatty" answers with anything but a move.
+func runBot(behavior string) {
+	scanner := bufio.NewScanner(os.Stdin)
+
+	var (
+		holes int
+		state [][]board.CellValue
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "proxx":
+			fmt.Println("id name test-" + behavior)
+			fmt.Println("proxxok")
+		case "newgame":
+			_, _ = fmt.Sscan(fields[3], &holes)
+		case "board":
+			state = nil
+
+			for _, row := range fields[1:] {
+				var values []board.CellValue
+				for _, v := range row {
+					values = append(values, board.CellValue(v))
+				}
+
+				state = append(state, values)
+			}
+		case "go":
+			switch behavior {
+			case "crash":
+				os.Exit(1)
+			case "hang":
+				time.Sleep(time.Hour)
+			case "illegal":
+				fmt.Println("move open -1 0")
+				continue
+			case "chatty":
+				fmt.Println("info thinking")
+				fmt.Println("I'd rather not")
+				continue
+			}
+
+			fmt.Println("info thinking")
+
+			pos := nextMove(state, holes)
+			fmt.Printf("move open %d %d\n", pos.Row, pos.Col)
+		case "quit":
+			return
+		}
+	}
+}
+
+func nextMove(state [][]board.CellValue, holes int) board.Position {
+	for _, m := range solver.Solve(state, holes) {
+		if !m.BlackHole && state[m.Position.Row][m.Position.Col].IsHidden() {
+			return m.Position
+		}
+	}
+
+	for i, row := range state {
+		for j, v := range row {
+			if v == board.CellValueUnknown {
+				return board.Position{Row: i, Col: j}
+			}
+		}
+	}
+
+	return board.Position{}
+}
+
+func newConfig(t *testing.T, behavior string, games int) arena.Config {
+	t.Setenv(botEnv, behavior)
+
+	return arena.Config{
+		Command: []string{os.Args[0]},
+		Games:   games,
+		Seed:    42,
+		GameConfig: func(seed int64) (game.Config, error) {
+			return game.Config{NumRows: 9, NumCols: 9, NumBlackHoles: 10, FirstClickSafe: true,
+				BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(seed)}, nil
+		},
+		MoveTime: 5 * time.Second,
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := arena.Run(newConfig(t, "solver", 5))
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-solver", report.Bot)
+	require.Len(t, report.Games, 5)
+
+	for i, g := range report.Games {
+		assert.Equal(t, int64(42+i), g.Seed)
+		assert.Contains(t, []arena.Outcome{arena.OutcomeWon, arena.OutcomeLost}, g.Outcome)
+		assert.NoError(t, g.Err)
+		assert.Positive(t, g.Moves)
+		assert.Positive(t, g.Progress)
+
+		if g.Outcome == arena.OutcomeWon {
+			assert.Equal(t, 1.0, g.Progress)
+		}
+	}
+
+	assert.Equal(t, 5, report.Count(arena.OutcomeWon)+report.Count(arena.OutcomeLost))
+	assert.Positive(t, report.AverageProgress())
+	assert.Positive(t, report.Time())
+
+	again, err := arena.Run(newConfig(t, "solver", 5))
+	require.NoError(t, err)
+
+	for i := range report.Games {
+		assert.Equal(t, report.Games[i].Outcome, again.Games[i].Outcome, "the same seed should give the same game")
+		assert.Equal(t, report.Games[i].Moves, again.Games[i].Moves, "the same seed should give the same game")
+	}
+}
+
+func TestRun_FailingBots(t *testing.T) {
+	tests := []struct {
+		behavior string
+		moveTime time.Duration
+		outcome  arena.Outcome
+		err      error
+	}{
+		{behavior: "crash", moveTime: 5 * time.Second, outcome: arena.OutcomeCrash, err: arena.ErrBotExited},
+		{behavior: "hang", moveTime: 100 * time.Millisecond, outcome: arena.OutcomeTimeout, err: arena.ErrBotTimeout},
+		{behavior: "illegal", moveTime: 5 * time.Second, outcome: arena.OutcomeIllegal, err: arena.ErrIllegalMove},
+		{behavior: "chatty", moveTime: 5 * time.Second, outcome: arena.OutcomeIllegal, err: arena.ErrIllegalMove},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.behavior, func(t *testing.T) {
+			cfg := newConfig(t, tc.behavior, 2)
+			cfg.MoveTime = tc.moveTime
+
+			report, err := arena.Run(cfg)
+			require.NoError(t, err)
+
+			require.Len(t, report.Games, 2)
+			assert.Equal(t, 2, report.Count(tc.outcome), "every game should end the same way")
+
+			for _, g := range report.Games {
+				assert.ErrorIs(t, g.Err, tc.err)
+				assert.Zero(t, g.Progress)
+			}
+
+			assert.Zero(t, report.WinRate())
+		})
+	}
+}
+
+func TestRun_MissingBot(t *testing.T) {
+	cfg := newConfig(t, "solver", 1)
+	cfg.Command = []string{"./no-such-bot"}
+
+	_, err := arena.Run(cfg)
+	assert.Error(t, err)
+}
+
+func TestParseMove(t *testing.T) {
+	t.Parallel()
+
+	m, err := arena.ParseMove("move chord 3 4")
+	require.NoError(t, err)
+	assert.Equal(t, game.Move{Kind: game.MoveChord, Position: board.Position{Row: 3, Col: 4}}, m)
+
+	for _, line := range []string{"move", "move dig 1 2", "move open a 2", "move open 1 b", "open 1 2", "move open 1 2 3"} {
+		_, err := arena.ParseMove(line)
+		assert.ErrorIs(t, err, arena.ErrIllegalMove, line)
+	}
+}
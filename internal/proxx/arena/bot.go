@@ -0,0 +1,170 @@
+package arena
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// handshakeTimeout limits the time a bot may take to start and to answer "proxx".
+	handshakeTimeout = 10 * time.Second
+	// quitTimeout limits the time a bot may take to exit after "quit" before it's killed.
+	quitTimeout = 2 * time.Second
+)
+
+var (
+	ErrBotTimeout  = errors.New("the bot didn't answer in time")
+	ErrBotExited   = errors.New("the bot exited")
+	ErrIllegalMove = errors.New("illegal move")
+)
+
+// bot is a running bot process. Lines written by the bot are read in the background,
+// so waiting for an answer can be limited in time.
+type bot struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string
+	name  string
+}
+
+// startBot starts the command and makes the handshake. Errors of starting the process are returned as they are,
+// a bot that fails the handshake is killed and the returned error wraps ErrBotTimeout or ErrBotExited.
+func startBot(command []string, stderr io.Writer) (*bot, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = stderr
+	// children of the bot may keep its output open, so waiting for a killed bot is limited
+	cmd.WaitDelay = quitTimeout
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	b := &bot{cmd: cmd, stdin: stdin, lines: make(chan string), name: command[0]}
+
+	go func() {
+		defer close(b.lines)
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			b.lines <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+
+	if err := b.handshake(); err != nil {
+		b.kill()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// handshake sends "proxx" and waits for "proxxok", the name of the bot is taken from "id name".
+func (b *bot) handshake() error {
+	if err := b.send("proxx"); err != nil {
+		return fmt.Errorf("handshake failed: %w", err)
+	}
+
+	deadline := time.Now().Add(handshakeTimeout)
+
+	for {
+		line, err := b.receive(time.Until(deadline))
+		if err != nil {
+			return fmt.Errorf("handshake failed: %w", err)
+		}
+
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			b.name = strings.TrimSpace(name)
+			continue
+		}
+
+		if line == "proxxok" {
+			return nil
+		}
+	}
+}
+
+// send writes a line to the bot. A bot that doesn't read its input anymore is considered exited.
+func (b *bot) send(format string, args ...any) error {
+	if _, err := fmt.Fprintf(b.stdin, format+"\n", args...); err != nil {
+		return fmt.Errorf("%w: %w", ErrBotExited, err)
+	}
+
+	return nil
+}
+
+// receive returns the next line written by the bot within the timeout. Empty lines and "info" lines are skipped.
+func (b *bot) receive(timeout time.Duration) (string, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				return "", ErrBotExited
+			}
+
+			if line == "" || line == "info" || strings.HasPrefix(line, "info ") {
+				continue
+			}
+
+			return line, nil
+		case <-timer.C:
+			return "", ErrBotTimeout
+		}
+	}
+}
+
+// stop asks the bot to quit and kills it if it doesn't.
+func (b *bot) stop() {
+	_ = b.send("quit")
+	_ = b.stdin.Close()
+
+	timer := time.NewTimer(quitTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-b.lines:
+			if ok {
+				continue
+			}
+
+			_ = b.cmd.Wait()
+
+			return
+		case <-timer.C:
+			b.kill()
+			return
+		}
+	}
+}
+
+// kill stops the bot at once.
+func (b *bot) kill() {
+	_ = b.cmd.Process.Kill()
+	_ = b.stdin.Close()
+
+	go func() {
+		// the rest of the output is of no interest, it's read only to let the reader finish
+		for range b.lines {
+		}
+	}()
+
+	_ = b.cmd.Wait()
+}
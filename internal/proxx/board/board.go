@@ -21,7 +21,7 @@ type Board struct {
 // NewBoard return a new board with the specified number of rows and columns.
 // The newly created board filled with only blank cells.
 func NewBoard(cfg Config) (*Board, error) {
-	if err := cfg.validate(); err != nil {
+	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
@@ -48,6 +48,33 @@ func (b *Board) Init(bhs []Position) {
 	b.populateWithClues()
 }
 
+// RelocateBlackHole moves the black hole at the specified position to another cell without a black hole.
+// pick receives the number of such cells and returns the index of the chosen one, counting row by row
+// from the top-left corner. Clues are updated accordingly. Returns the new position of the black hole.
+func (b *Board) RelocateBlackHole(from Position, pick func(n int) int) Position {
+	var free []Position
+
+	for i := 0; i < b.height(); i++ {
+		for j := 0; j < b.width(); j++ {
+			if pos := (Position{Row: i, Col: j}); pos != from && !b.CellAt(i, j).IsBlackHole() {
+				free = append(free, pos)
+			}
+		}
+	}
+
+	if len(free) == 0 {
+		return from
+	}
+
+	to := free[pick(len(free))]
+
+	b.putBlackHoleAt(to.Row, to.Col)
+	b.putClueAt(valueBlank, from.Row, from.Col)
+	b.populateWithClues()
+
+	return to
+}
+
 func (b *Board) populateWithBlackHoles(bhs []Position) {
 	for _, bh := range bhs {
 		b.putBlackHoleAt(bh.Row, bh.Col)
@@ -115,15 +142,36 @@ func (b *Board) numberOfAdjacentBlackHoles(i int, j int) int {
 	return n
 }
 
+// BlackHolePositions returns positions of all the black holes on the board.
+func (b *Board) BlackHolePositions() []Position {
+	var positions []Position
+
+	for i := 0; i < b.height(); i++ {
+		for j := 0; j < b.width(); j++ {
+			if b.CellAt(i, j).IsBlackHole() {
+				positions = append(positions, Position{Row: i, Col: j})
+			}
+		}
+	}
+
+	return positions
+}
+
 // OpenAllBlackHoles marks all the black holes on the board as opened.
-func (b *Board) OpenAllBlackHoles() {
+// Returns positions of the black holes that weren't opened before.
+func (b *Board) OpenAllBlackHoles() []Position {
+	var opened []Position
+
 	for i := 0; i < b.height(); i++ {
 		for j := 0; j < b.width(); j++ {
-			if c := b.CellAt(i, j); c.IsBlackHole() {
+			if c := b.CellAt(i, j); c.IsBlackHole() && !c.IsOpen() {
 				c.MarkAsOpen()
+				opened = append(opened, Position{Row: i, Col: j})
 			}
 		}
 	}
+
+	return opened
 }
 
 func (b *Board) GetSurroundingCellPositions(i, j int) []Position {
@@ -149,13 +197,31 @@ func (b *Board) GetSurroundingCellPositions(i, j int) []Position {
 	return result
 }
 
+// SurroundingPositions returns positions of the cells surrounding the specified cell
+// on a board with the specified number of rows and columns.
+func SurroundingPositions(rows int, cols int, row int, col int) []Position {
+	positions := make([]Position, 0, 8)
+
+	for i := row - 1; i <= row+1; i++ {
+		for j := col - 1; j <= col+1; j++ {
+			if (i == row && j == col) || i < 0 || j < 0 || i >= rows || j >= cols {
+				continue
+			}
+
+			positions = append(positions, Position{Row: i, Col: j})
+		}
+	}
+
+	return positions
+}
+
 // ValidCellPosition checks if the cell with the specified coordinates is located on the board.
 func (b *Board) ValidCellPosition(row int, col int) bool {
 	return row >= 0 && col >= 0 && row <= b.height()-1 && col <= b.width()-1
 }
 
 // State returns the current state of the board as a two-dimensional matrix of cell values.
-// Reveals only opened cells.
+// Reveals only opened cells, closed cells with a flag are marked as flagged.
 func (b *Board) State() [][]CellValue {
 	s := make([][]CellValue, 0, b.height())
 
@@ -167,6 +233,8 @@ func (b *Board) State() [][]CellValue {
 			value := CellValue(CellValueUnknown)
 			if cell.IsOpen() {
 				value = cell.Value()
+			} else if cell.IsFlagged() {
+				value = CellValueFlag
 			}
 
 			row = append(row, value)
@@ -73,3 +73,45 @@ func TestBoard_Init(t *testing.T) {
 		assert.EqualValues(t, 0, gameBoard.OpenedCells())
 	})
 }
+
+func TestBoard_RelocateBlackHole(t *testing.T) {
+	t.Run("Black hole is moved to the picked free cell", func(t *testing.T) {
+		t.Parallel()
+
+		gameBoard, err := board.NewBoard(board.Config{NumRows: 3, NumCols: 3})
+		require.NoError(t, err)
+
+		gameBoard.Init([]board.Position{{0, 0}, {1, 1}})
+
+		var free int
+
+		to := gameBoard.RelocateBlackHole(board.Position{Row: 1, Col: 1}, func(n int) int {
+			free = n
+			return 1
+		})
+
+		assert.Equal(t, 7, free)
+		assert.Equal(t, board.Position{Row: 0, Col: 2}, to)
+		testhelpers.EqualBoardStates(t, [][]board.CellValue{
+			{"H", "2", "H"},
+			{"1", "2", "1"},
+			{"0", "0", "0"},
+		}, gameBoard.DebugState())
+	})
+
+	t.Run("No free cell", func(t *testing.T) {
+		t.Parallel()
+
+		gameBoard, err := board.NewBoard(board.Config{NumRows: 1, NumCols: 2})
+		require.NoError(t, err)
+
+		gameBoard.Init([]board.Position{{0, 0}, {0, 1}})
+
+		to := gameBoard.RelocateBlackHole(board.Position{Row: 0, Col: 1}, func(int) int {
+			t.Fatal("pick must not be called without free cells")
+			return 0
+		})
+
+		assert.Equal(t, board.Position{Row: 0, Col: 1}, to)
+	})
+}
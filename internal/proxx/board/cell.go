@@ -8,11 +8,29 @@ const (
 	CellValueBlackHole = "H"
 	CellValueBlank     = "0"
 	CellValueUnknown   = "?"
+	CellValueFlag      = "F"
 )
 
+// IsHidden checks whether the value hides the content of a cell.
+// Flagged cells are hidden as well: a flag is a player's guess, not a revealed fact.
+func (v CellValue) IsHidden() bool {
+	return v == CellValueUnknown || v == CellValueFlag
+}
+
+// Clue returns the number of adjacent black holes if the value is a clue or a blank cell.
+func (v CellValue) Clue() (int, bool) {
+	value, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
+
 type Cell struct {
-	value  int
-	isOpen bool
+	value     int
+	isOpen    bool
+	isFlagged bool
 }
 
 func newBlankCell() *Cell {
@@ -39,6 +57,15 @@ func (c *Cell) IsOpen() bool {
 	return c.isOpen
 }
 
+// ToggleFlag puts a flag on the cell or removes it.
+func (c *Cell) ToggleFlag() {
+	c.isFlagged = !c.isFlagged
+}
+
+func (c *Cell) IsFlagged() bool {
+	return c.isFlagged
+}
+
 func (c *Cell) PutClue(value int) {
 	c.value = value
 }
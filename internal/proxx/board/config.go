@@ -13,8 +13,8 @@ type Config struct {
 	NumCols int
 }
 
-// validate checks the board's configuration.
-func (cfg Config) validate() error {
+// Validate checks the board's configuration.
+func (cfg Config) Validate() error {
 	if cfg.NumRows < 1 {
 		return ErrInvalidNumberOfRows
 	}
@@ -0,0 +1,251 @@
+// Package command implements the in-game command language shared by the game's front ends:
+// a parser of commands like "o 3,4" or "undo" and a dispatcher that executes them on a game.
+package command
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/board"
+	"strings"
+)
+
+var (
+	ErrEmptyCommand       = errors.New("empty command")
+	ErrUnknownCommand     = errors.New("unknown command")
+	ErrPositionExpected   = errors.New("cell position should be provided")
+	ErrInvalidPosition    = errors.New("cell position should be a row and a column like 3,4 or a column letter and a row like D3")
+	ErrFileNameExpected   = errors.New("file name should be provided")
+	ErrUnexpectedArgument = errors.New("command doesn't take arguments")
+)
+
+// Kind represents a kind of command.
+type Kind int
+
+const (
+	Open Kind = iota
+	Flag
+	Chord
+	Hint
+	Undo
+	Redo
+	Save
+	Load
+	New
+	Help
+	Stats
+	Quit
+)
+
+// argument represents the kind of command's argument.
+type argument int
+
+const (
+	noArgument argument = iota
+	positionArgument
+	pathArgument
+)
+
+// spec describes a command: its name goes first, then the aliases.
+type spec struct {
+	kind        Kind
+	names       []string
+	arg         argument
+	description string
+}
+
+var specs = []spec{
+	{kind: Open, names: []string{"open", "o"}, arg: positionArgument, description: "open the cell"},
+	{kind: Flag, names: []string{"flag", "f"}, arg: positionArgument, description: "put a flag on the cell or remove it"},
+	{kind: Chord, names: []string{"chord", "c"}, arg: positionArgument,
+		description: "open the cells around the clue that has as many flags around it as its number"},
+	{kind: Hint, names: []string{"hint", "h"}, description: "suggest the next move"},
+	{kind: Undo, names: []string{"undo", "u"}, description: "take back the last move"},
+	{kind: Redo, names: []string{"redo", "r"}, description: "make the undone move again"},
+	{kind: Save, names: []string{"save"}, arg: pathArgument, description: "save the game to the file"},
+	{kind: Load, names: []string{"load"}, arg: pathArgument, description: "load the game from the file"},
+	{kind: New, names: []string{"new", "n"}, description: "start a new game"},
+	{kind: Help, names: []string{"help", "?"}, description: "show this help"},
+	{kind: Stats, names: []string{"stats"}, description: "show statistics of the game"},
+	{kind: Quit, names: []string{"quit", "q", "exit"}, description: "leave the game"},
+}
+
+func (k Kind) String() string {
+	for _, s := range specs {
+		if s.kind == k {
+			return s.names[0]
+		}
+	}
+
+	return "unknown"
+}
+
+// Command represents a parsed command. Position is set for open, flag and chord, Path for save and load.
+type Command struct {
+	Kind     Kind
+	Position board.Position
+	Path     string
+}
+
+// Parse parses a command line like "o 3,4", "flag C2", "save game.json" or "undo" using the default notation.
+func Parse(line string) (Command, error) {
+	return DefaultNotation.Parse(line)
+}
+
+// Parse parses a command line like "o 3,4", "flag C2", "save game.json" or "undo".
+// Names are case-insensitive, positions are written in the notation. A bare position opens the cell.
+// An unknown command is reported with a suggestion of the closest known one.
+func (n Notation) Parse(line string) (Command, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Command{}, ErrEmptyCommand
+	}
+
+	if line[0] >= '0' && line[0] <= '9' {
+		pos, err := n.ParsePosition(line)
+		if err != nil {
+			return Command{}, err
+		}
+
+		return Command{Kind: Open, Position: pos}, nil
+	}
+
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	s, ok := lookup(strings.ToLower(name))
+	if !ok {
+		if pos, err := n.ParsePosition(line); err == nil {
+			return Command{Kind: Open, Position: pos}, nil
+		}
+
+		return Command{}, unknownCommandError(name)
+	}
+
+	cmd := Command{Kind: s.kind}
+
+	switch s.arg {
+	case positionArgument:
+		if rest == "" {
+			return Command{}, fmt.Errorf("%s: %w", s.names[0], ErrPositionExpected)
+		}
+
+		pos, err := n.ParsePosition(rest)
+		if err != nil {
+			return Command{}, fmt.Errorf("%s: %w", s.names[0], err)
+		}
+
+		cmd.Position = pos
+	case pathArgument:
+		if rest == "" {
+			return Command{}, fmt.Errorf("%s: %w", s.names[0], ErrFileNameExpected)
+		}
+
+		cmd.Path = rest
+	default:
+		if rest != "" {
+			return Command{}, fmt.Errorf("%s: %w", s.names[0], ErrUnexpectedArgument)
+		}
+	}
+
+	return cmd, nil
+}
+
+// Usage returns the description of all the commands.
+func Usage() string {
+	var b strings.Builder
+
+	b.WriteString("Commands (short aliases in brackets):\n")
+
+	for _, s := range specs {
+		usage := s.names[0]
+
+		switch s.arg {
+		case positionArgument:
+			usage += " <cell>"
+		case pathArgument:
+			usage += " <file>"
+		}
+
+		if len(s.names) > 1 {
+			usage += " (" + strings.Join(s.names[1:], ", ") + ")"
+		}
+
+		fmt.Fprintf(&b, "  %-28s %s\n", usage, s.description)
+	}
+
+	b.WriteString("A cell is written as \"row,col\" (3,4) or as a column letter followed by a row (D3).\n")
+	b.WriteString("A bare cell opens it.\n")
+
+	return b.String()
+}
+
+func lookup(name string) (spec, bool) {
+	for _, s := range specs {
+		for _, n := range s.names {
+			if n == name {
+				return s, true
+			}
+		}
+	}
+
+	return spec{}, false
+}
+
+// maxSuggestionDistance limits how far a typo may be from the suggested command.
+const maxSuggestionDistance = 2
+
+// unknownCommandError reports the unknown command with the closest known command name, if there is one.
+func unknownCommandError(name string) error {
+	lower := strings.ToLower(name)
+
+	var (
+		suggestion string
+		best       = maxSuggestionDistance + 1
+	)
+
+	for _, s := range specs {
+		for _, n := range s.names {
+			// one-letter aliases are too short to be suggested
+			if len(n) < 2 {
+				continue
+			}
+
+			if d := distance(lower, n); d < best && d < len(n) {
+				suggestion, best = n, d
+			}
+		}
+	}
+
+	if suggestion == "" {
+		return fmt.Errorf("%w %q, type \"help\" to see all the commands", ErrUnknownCommand, name)
+	}
+
+	return fmt.Errorf("%w %q, did you mean %q?", ErrUnknownCommand, name, suggestion)
+}
+
+// distance returns the Levenshtein distance between the strings.
+func distance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,100 @@
+package command_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	pos := board.Position{Row: 2, Col: 3}
+
+	testCases := []struct {
+		name     string
+		line     string
+		expected command.Command
+		err      error
+	}{
+		{name: "Open", line: "open 3,4", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Open alias", line: "o 3,4", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Bare position", line: "3,4", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Spaces around numbers", line: "  O  3 , 4 ", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Flag", line: "f 3,4", expected: command.Command{Kind: command.Flag, Position: pos}},
+		{name: "Chord", line: "CHORD 3,4", expected: command.Command{Kind: command.Chord, Position: pos}},
+		{name: "Hint", line: "hint", expected: command.Command{Kind: command.Hint}},
+		{name: "Undo", line: "u", expected: command.Command{Kind: command.Undo}},
+		{name: "Redo", line: "redo", expected: command.Command{Kind: command.Redo}},
+		{name: "Save", line: "save my game.json", expected: command.Command{Kind: command.Save, Path: "my game.json"}},
+		{name: "Load", line: "load game.json", expected: command.Command{Kind: command.Load, Path: "game.json"}},
+		{name: "New", line: "new", expected: command.Command{Kind: command.New}},
+		{name: "Help", line: "?", expected: command.Command{Kind: command.Help}},
+		{name: "Stats", line: "stats", expected: command.Command{Kind: command.Stats}},
+		{name: "Quit", line: "exit", expected: command.Command{Kind: command.Quit}},
+		{name: "Empty", line: "  ", err: command.ErrEmptyCommand},
+		{name: "Unknown", line: "dance", err: command.ErrUnknownCommand},
+		{name: "Missing position", line: "flag", err: command.ErrPositionExpected},
+		{name: "Invalid position", line: "o 3,4,5", err: command.ErrInvalidPosition},
+		{name: "Spreadsheet position", line: "f D3", expected: command.Command{Kind: command.Flag, Position: pos}},
+		{name: "Bare spreadsheet position", line: "d3", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Other separator", line: "c 3;4", expected: command.Command{Kind: command.Chord, Position: pos}},
+		{name: "Spreadsheet position with a space", line: "D 3", expected: command.Command{Kind: command.Open, Position: pos}},
+		{name: "Alias with an invalid position", line: "c 4", err: command.ErrInvalidPosition},
+		{name: "Invalid position after an alias", line: "c 4,x", err: command.ErrInvalidPosition},
+		{name: "Negative bare position", line: "-1,2", err: command.ErrUnknownCommand},
+		{name: "Invalid bare position", line: "3", err: command.ErrInvalidPosition},
+		{name: "Missing file", line: "save", err: command.ErrFileNameExpected},
+		{name: "Unexpected argument", line: "undo 3", err: command.ErrUnexpectedArgument},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := command.Parse(tc.line)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestParse_Suggestions(t *testing.T) {
+	testCases := []struct {
+		line       string
+		suggestion string
+	}{
+		{line: "falg 1,1", suggestion: `did you mean "flag"?`},
+		{line: "opne 1,1", suggestion: `did you mean "open"?`},
+		{line: "udno", suggestion: `did you mean "undo"?`},
+		{line: "Hnit", suggestion: `did you mean "hint"?`},
+		{line: "stat", suggestion: `did you mean "stats"?`},
+		{line: "xyzzy", suggestion: `type "help" to see all the commands`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.line, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := command.Parse(tc.line)
+			require.ErrorIs(t, err, command.ErrUnknownCommand)
+			assert.Contains(t, err.Error(), tc.suggestion)
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	usage := command.Usage()
+
+	for _, name := range []string{"open", "flag", "chord", "hint", "undo", "redo", "save", "load", "new", "help", "stats", "quit"} {
+		assert.Contains(t, usage, name)
+	}
+}
@@ -0,0 +1,194 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"time"
+)
+
+var ErrUnsupported = errors.New("command isn't supported here")
+
+// Config connects the dispatcher with a front end. NewGame, Save and Load create, save and load games,
+// a command whose handler is nil is reported as unsupported. Notation is used to write positions in messages.
+type Config struct {
+	NewGame  func() (*game.Game, error)
+	Save     func(g *game.Game, path string) error
+	Load     func(path string) (*game.Game, error)
+	Notation Notation
+}
+
+// Result represents the outcome of a command.
+type Result struct {
+	Command Command
+	// Open is the outcome of open and chord commands.
+	Open game.OpenResult
+	// Hint is the outcome of the hint command.
+	Hint game.Hint
+	// Message describes the outcome for a player, it's empty if the board tells everything.
+	Message string
+	// GameChanged is true if the game was replaced by new or load commands.
+	GameChanged bool
+	// Quit is true if the player wants to leave.
+	Quit bool
+}
+
+// Dispatcher executes commands on a game.
+type Dispatcher struct {
+	game *game.Game
+	cfg  Config
+}
+
+// NewDispatcher creates a dispatcher that executes commands on the game.
+func NewDispatcher(g *game.Game, cfg Config) *Dispatcher {
+	return &Dispatcher{game: g, cfg: cfg}
+}
+
+// Game returns the game the commands are executed on. It changes after new and load commands.
+func (d *Dispatcher) Game() *game.Game {
+	return d.game
+}
+
+// ExecuteLine parses the command line written in the dispatcher's notation and executes the command.
+func (d *Dispatcher) ExecuteLine(line string) (Result, error) {
+	cmd, err := d.cfg.Notation.Parse(line)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return d.Execute(cmd)
+}
+
+// Execute executes the command. Errors of the game package are returned as is.
+func (d *Dispatcher) Execute(cmd Command) (Result, error) {
+	result := Result{Command: cmd}
+	pos := cmd.Position
+
+	var err error
+
+	switch cmd.Kind {
+	case Open:
+		result.Open, err = d.game.OpenCell(pos.Row, pos.Col)
+	case Flag:
+		err = d.game.ToggleFlag(pos.Row, pos.Col)
+	case Chord:
+		result.Open, err = d.game.Chord(pos.Row, pos.Col)
+	case Hint:
+		result.Hint, err = d.game.Hint()
+		result.Message = FormatHint(result.Hint, d.cfg.Notation)
+	case Undo:
+		err = d.game.Undo()
+	case Redo:
+		err = d.game.Redo()
+	case Save:
+		if d.cfg.Save == nil {
+			return Result{}, fmt.Errorf("%s: %w", cmd.Kind, ErrUnsupported)
+		}
+
+		err = d.cfg.Save(d.game, cmd.Path)
+		result.Message = fmt.Sprintf("The game is saved to %s.", cmd.Path)
+	case Load:
+		if d.cfg.Load == nil {
+			return Result{}, fmt.Errorf("%s: %w", cmd.Kind, ErrUnsupported)
+		}
+
+		err = d.replaceGame(d.cfg.Load(cmd.Path))
+		result.GameChanged = true
+		result.Message = fmt.Sprintf("The game is loaded from %s.", cmd.Path)
+	case New:
+		if d.cfg.NewGame == nil {
+			return Result{}, fmt.Errorf("%s: %w", cmd.Kind, ErrUnsupported)
+		}
+
+		err = d.replaceGame(d.cfg.NewGame())
+		result.GameChanged = true
+		result.Message = "Your game is ready!"
+	case Help:
+		result.Message = Usage()
+	case Stats:
+		result.Message = FormatStats(d.game)
+	case Quit:
+		result.Quit = true
+	default:
+		return Result{}, fmt.Errorf("%w: %d", ErrUnknownCommand, cmd.Kind)
+	}
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+func (d *Dispatcher) replaceGame(g *game.Game, err error) error {
+	if err != nil {
+		return err
+	}
+
+	d.game = g
+
+	return nil
+}
+
+// FormatHint describes the hint for a player, the positions are written in the notation.
+func FormatHint(h game.Hint, n Notation) string {
+	cell := n.FormatPosition(h.Position)
+	reason := h.Reason.Explain(n.FormatPosition)
+
+	switch {
+	case h.Certain && h.BlackHole:
+		return fmt.Sprintf("Hint: %s is a black hole, because %s.", cell, reason)
+	case h.Certain:
+		return fmt.Sprintf("Hint: %s is safe to open, because %s.", cell, reason)
+	default:
+		return fmt.Sprintf("Hint: %s is the safest guess, %.0f%% chance of a black hole.", cell, h.Probability*100)
+	}
+}
+
+// FormatStats describes the game's statistics for a player.
+func FormatStats(g *game.Game) string {
+	flags := 0
+
+	for _, row := range g.BoardState() {
+		for _, v := range row {
+			if v == board.CellValueFlag {
+				flags++
+			}
+		}
+	}
+
+	stats := g.Stats()
+	elapsed := g.Elapsed().Round(time.Second)
+
+	return fmt.Sprintf("Time: %02d:%02d, moves: %d, flags: %d of %d black holes, hints used: %d, undos: %d.",
+		int(elapsed.Minutes()), int(elapsed.Seconds())%60, len(g.History()), flags, g.NumBlackHoles(),
+		stats.HintsUsed, stats.Undos)
+}
+
+// SaveFile saves the game to the file at path.
+func SaveFile(g *game.Game, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Save(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// LoadFile loads the game from the file at path.
+func LoadFile(path string) (*game.Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return game.Load(f)
+}
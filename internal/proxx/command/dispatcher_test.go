@@ -0,0 +1,145 @@
+package command_test
+
+import (
+	"path/filepath"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixedGame creates the game on the board:
+//
+//	0 1 1
+//	0 2 H
+//	0 2 H
+func newFixedGame(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	})
+	require.NoError(t, err)
+
+	return g
+}
+
+func execute(t *testing.T, d *command.Dispatcher, lines ...string) command.Result {
+	t.Helper()
+
+	var result command.Result
+
+	for _, line := range lines {
+		var err error
+
+		result, err = d.ExecuteLine(line)
+		require.NoError(t, err, line)
+	}
+
+	return result
+}
+
+func TestDispatcher_Execute(t *testing.T) {
+	t.Run("Play the game to the win", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		execute(t, d, "o 1,3", "f 2,3", "c 1,3", "u", "r")
+
+		result := execute(t, d, "open 3,1")
+		assert.Equal(t, game.StatusWon, result.Open.Status)
+		assert.True(t, d.Game().IsWon())
+	})
+
+	t.Run("Hint", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		result := execute(t, d, "1,1", "hint")
+		assert.True(t, result.Hint.Certain)
+		assert.Equal(t, "Hint: 1,3 is safe to open, because the 1 at (1,2) is already satisfied by the black hole at (2,3).",
+			result.Message)
+	})
+
+	t.Run("Hint in the zero-based notation", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{Notation: command.Notation{ZeroBased: true}})
+
+		result := execute(t, d, "0,0", "hint")
+		assert.Equal(t, "Hint: 0,2 is safe to open, because the 1 at (0,1) is already satisfied by the black hole at (1,2).",
+			result.Message)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		result := execute(t, d, "1,3", "f 2,3", "u", "stats")
+		assert.Contains(t, result.Message, "moves: 1, flags: 0 of 2 black holes, hints used: 0, undos: 1")
+	})
+
+	t.Run("Game errors are returned as is", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		_, err := d.ExecuteLine("c 1,1")
+		assert.ErrorIs(t, err, game.ErrCellNotOpen)
+
+		_, err = d.ExecuteLine("o 4,4")
+		assert.ErrorIs(t, err, game.ErrCellPositionIsOutsideBoard)
+
+		_, err = d.ExecuteLine("undo")
+		assert.ErrorIs(t, err, game.ErrNothingToUndo)
+	})
+
+	t.Run("Commands without handlers are unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		for _, line := range []string{"new", "save game.json", "load game.json"} {
+			_, err := d.ExecuteLine(line)
+			assert.ErrorIs(t, err, command.ErrUnsupported, line)
+		}
+	})
+
+	t.Run("Save, load and new game", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "game.json")
+		d := command.NewDispatcher(newFixedGame(t), command.Config{
+			NewGame: func() (*game.Game, error) { return newFixedGame(t), nil },
+			Save:    command.SaveFile,
+			Load:    command.LoadFile,
+		})
+
+		execute(t, d, "o 1,3", "save "+path)
+
+		result := execute(t, d, "new")
+		assert.True(t, result.GameChanged)
+		assert.Empty(t, d.Game().History())
+
+		result = execute(t, d, "load "+path)
+		assert.True(t, result.GameChanged)
+		assert.Len(t, d.Game().History(), 1)
+	})
+
+	t.Run("Quit", func(t *testing.T) {
+		t.Parallel()
+
+		d := command.NewDispatcher(newFixedGame(t), command.Config{})
+
+		assert.True(t, execute(t, d, "q").Quit)
+	})
+}
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Notation describes how cell positions are written.
+// A position is either a row and a column separated by a comma, spaces or other punctuation ("3,4", "3 4", "3:4"),
+// or a spreadsheet-style column letter followed by a row ("D3", "AA12" for wide boards).
+// Rows and numeric columns are numbered from 1 unless ZeroBased is true. Column letters always start from A.
+type Notation struct {
+	ZeroBased bool
+}
+
+// DefaultNotation numbers rows and columns from 1.
+var DefaultNotation = Notation{}
+
+// ParsePosition parses the position written in the default notation.
+func ParsePosition(s string) (board.Position, error) {
+	return DefaultNotation.ParsePosition(s)
+}
+
+// FormatPosition formats the position in the default notation.
+func FormatPosition(p board.Position) string {
+	return DefaultNotation.FormatPosition(p)
+}
+
+// ParsePosition parses the position written in the notation.
+func (n Notation) ParsePosition(s string) (board.Position, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return board.Position{}, ErrInvalidPosition
+	}
+
+	if isLetter(rune(s[0])) {
+		return n.parseSpreadsheetPosition(s)
+	}
+
+	for _, r := range s {
+		if isLetter(r) {
+			return board.Position{}, ErrInvalidPosition
+		}
+	}
+
+	values := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	if len(values) != 2 || !unicode.IsDigit(rune(s[0])) {
+		return board.Position{}, ErrInvalidPosition
+	}
+
+	// "3-4" uses the dash as a separator, while in "3,-4" it is the sign of the column
+	separator := s[len(values[0]) : len(values[0])+strings.Index(s[len(values[0]):], values[1])]
+	if len(separator) > 1 && strings.ContainsAny(separator[len(separator)-1:], "+-") {
+		return board.Position{}, ErrInvalidPosition
+	}
+
+	row, err := n.parseNumber(values[0])
+	if err != nil {
+		return board.Position{}, err
+	}
+
+	col, err := n.parseNumber(values[1])
+	if err != nil {
+		return board.Position{}, err
+	}
+
+	return board.Position{Row: row, Col: col}, nil
+}
+
+// parseSpreadsheetPosition parses a position like "C4" or "aa 12".
+func (n Notation) parseSpreadsheetPosition(s string) (board.Position, error) {
+	letters := strings.IndexFunc(s, func(r rune) bool { return !isLetter(r) })
+	if letters < 0 {
+		return board.Position{}, ErrInvalidPosition
+	}
+
+	col, err := ParseColumnLetters(s[:letters])
+	if err != nil {
+		return board.Position{}, err
+	}
+
+	row, err := n.parseNumber(strings.TrimSpace(s[letters:]))
+	if err != nil {
+		return board.Position{}, err
+	}
+
+	return board.Position{Row: row, Col: col}, nil
+}
+
+// parseNumber parses a number without a sign.
+func (n Notation) parseNumber(s string) (int, error) {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		return 0, ErrInvalidPosition
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidPosition
+	}
+
+	return value - n.Base(), nil
+}
+
+// Base returns the number of the first row or column: 0 or 1.
+func (n Notation) Base() int {
+	if n.ZeroBased {
+		return 0
+	}
+
+	return 1
+}
+
+// FormatPosition formats the position as "row,col".
+func (n Notation) FormatPosition(p board.Position) string {
+	return fmt.Sprintf("%d,%d", p.Row+n.Base(), p.Col+n.Base())
+}
+
+// RowLabel returns the label of the row.
+func (n Notation) RowLabel(row int) string {
+	return strconv.Itoa(row + n.Base())
+}
+
+// ColumnLetters returns the spreadsheet-style letters of the column: A for the first column,
+// Z for the 26th, then AA, AB and so on.
+func ColumnLetters(col int) string {
+	var letters []byte
+
+	for col++; col > 0; col = (col - 1) / 26 {
+		letters = append([]byte{byte('A' + (col-1)%26)}, letters...)
+	}
+
+	return string(letters)
+}
+
+// ParseColumnLetters returns the column with the specified spreadsheet-style letters, case is ignored.
+func ParseColumnLetters(s string) (int, error) {
+	if s == "" || len(s) > maxColumnLetters {
+		return 0, ErrInvalidPosition
+	}
+
+	col := 0
+
+	for _, r := range strings.ToUpper(s) {
+		if r < 'A' || r > 'Z' {
+			return 0, ErrInvalidPosition
+		}
+
+		col = col*26 + int(r-'A') + 1
+	}
+
+	return col - 1, nil
+}
+
+// maxColumnLetters keeps the column number far from overflow, 4 letters give about half a million columns.
+const maxColumnLetters = 4
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,94 @@
+package command_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotation_ParsePosition(t *testing.T) {
+	oneBased := command.Notation{}
+	zeroBased := command.Notation{ZeroBased: true}
+
+	testCases := []struct {
+		name     string
+		notation command.Notation
+		in       string
+		expected board.Position
+		err      error
+	}{
+		{name: "Comma", notation: oneBased, in: "3,4", expected: board.Position{Row: 2, Col: 3}},
+		{name: "Spaces", notation: oneBased, in: " 3   4 ", expected: board.Position{Row: 2, Col: 3}},
+		{name: "Other separators", notation: oneBased, in: "3 : 4", expected: board.Position{Row: 2, Col: 3}},
+		{name: "Spreadsheet", notation: oneBased, in: "C4", expected: board.Position{Row: 3, Col: 2}},
+		{name: "Spreadsheet in lower case", notation: oneBased, in: "c 4", expected: board.Position{Row: 3, Col: 2}},
+		{name: "Spreadsheet with two letters", notation: oneBased, in: "AA12", expected: board.Position{Row: 11, Col: 26}},
+		{name: "Zero-based", notation: zeroBased, in: "0,0", expected: board.Position{Row: 0, Col: 0}},
+		{name: "Zero-based spreadsheet", notation: zeroBased, in: "B0", expected: board.Position{Row: 0, Col: 1}},
+		{name: "One number", notation: oneBased, in: "3", err: command.ErrInvalidPosition},
+		{name: "Three numbers", notation: oneBased, in: "3,4,5", err: command.ErrInvalidPosition},
+		{name: "Letters only", notation: oneBased, in: "C", err: command.ErrInvalidPosition},
+		{name: "Letters between numbers", notation: oneBased, in: "3x4", err: command.ErrInvalidPosition},
+		{name: "Too many letters", notation: oneBased, in: "ABCDEFGHIJ1", err: command.ErrInvalidPosition},
+		{name: "Empty", notation: oneBased, in: " ", err: command.ErrInvalidPosition},
+		{name: "Dash as a separator", notation: oneBased, in: "3-4", expected: board.Position{Row: 2, Col: 3}},
+		{name: "Negative row", notation: zeroBased, in: "-1,2", err: command.ErrInvalidPosition},
+		{name: "Negative column", notation: zeroBased, in: "1, -2", err: command.ErrInvalidPosition},
+		{name: "Plus sign", notation: oneBased, in: "+1,2", err: command.ErrInvalidPosition},
+		{name: "Signed spreadsheet row", notation: oneBased, in: "C+4", err: command.ErrInvalidPosition},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pos, err := tc.notation.ParsePosition(tc.in)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pos)
+		})
+	}
+}
+
+func TestColumnLetters(t *testing.T) {
+	testCases := []struct {
+		col     int
+		letters string
+	}{
+		{col: 0, letters: "A"},
+		{col: 25, letters: "Z"},
+		{col: 26, letters: "AA"},
+		{col: 51, letters: "AZ"},
+		{col: 52, letters: "BA"},
+		{col: 701, letters: "ZZ"},
+		{col: 702, letters: "AAA"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.letters, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.letters, command.ColumnLetters(tc.col))
+
+			col, err := command.ParseColumnLetters(tc.letters)
+			require.NoError(t, err)
+			assert.Equal(t, tc.col, col)
+		})
+	}
+}
+
+func TestNotation_FormatPosition(t *testing.T) {
+	pos := board.Position{Row: 2, Col: 3}
+
+	assert.Equal(t, "3,4", command.Notation{}.FormatPosition(pos))
+	assert.Equal(t, "2,3", command.Notation{ZeroBased: true}.FormatPosition(pos))
+}
@@ -0,0 +1,221 @@
+// Package engine lets programs in other languages play games through JSON-RPC 2.0.
+//
+// Every message is a single line of JSON: requests (or batches of them) are read from the input,
+// responses and notifications are written to the output. The engine holds one game at a time;
+// the game's events are pushed as "event" notifications before the response to the request that caused them.
+//
+// Methods (positions are zero-based):
+//
+//	new    {"preset": "beginner"} or {"rows": 9, "cols": 9, "holes": 10} (or "density"),
+//	       optionally "seed", "locator" and "first_click_safe"; returns the state
+//	open   {"row": 0, "col": 0}; returns the opened cells and the state
+//	flag   {"row": 0, "col": 0}; puts a flag on the cell or removes it, returns the state
+//	chord  {"row": 0, "col": 0}; opens the cells around the clue, returns the opened cells and the state
+//	state  returns the state
+//	hint   returns a suggestion for the next move
+//	undo   takes back the last move, returns the state
+//	redo   makes the last undone move again, returns the state
+//	resign gives the game up, returns the state
+//	save   {"path": "game.json"} saves the game to the file, without the path the saved game is returned
+//	load   {"path": "game.json"} or {"game": {...}} loads the saved game, returns the state
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+)
+
+const jsonrpcVersion = "2.0"
+
+// request is a JSON-RPC request, a request without an ID is a notification that gets no response.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// Config represents the configuration of an engine.
+type Config struct {
+	// CustomPresets are available in addition to the built-in presets.
+	CustomPresets []game.Preset
+	// MaxCells limits the number of cells on a board, the default is used if it's zero.
+	MaxCells int
+}
+
+// Engine plays a game on behalf of a front end. It isn't safe for concurrent use.
+type Engine struct {
+	cfg Config
+
+	game        *game.Game
+	unsubscribe func()
+	// events are the notifications of the request being handled.
+	events []notification
+}
+
+// New creates an engine without a game, the front end starts one with the "new" method.
+func New(cfg Config) *Engine {
+	if cfg.MaxCells == 0 {
+		cfg.MaxCells = api.DefaultMaxCells
+	}
+
+	return &Engine{cfg: cfg, unsubscribe: func() {}}
+}
+
+// Serve handles the requests read from r until it ends, the responses and notifications are written to w.
+// Returns nil when r is exhausted or the error of reading or writing.
+func (e *Engine) Serve(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			if err := e.handleLine(bw, line); err != nil {
+				return err
+			}
+
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// handleLine handles a request or a batch and writes the notifications followed by the responses.
+func (e *Engine) handleLine(w io.Writer, line []byte) error {
+	line = bytes.TrimSpace(line)
+
+	var result any
+
+	if line[0] == '[' {
+		var batch []json.RawMessage
+
+		if err := json.Unmarshal(line, &batch); err != nil {
+			result = errorResponse(nil, newError(codeParseError, err.Error()))
+		} else if len(batch) == 0 {
+			result = errorResponse(nil, newError(codeInvalidRequest, "empty batch"))
+		} else {
+			var responses []response
+
+			for _, raw := range batch {
+				if resp, ok := e.handle(raw); ok {
+					responses = append(responses, resp)
+				}
+			}
+
+			if len(responses) > 0 {
+				result = responses
+			}
+		}
+	} else if resp, ok := e.handle(line); ok {
+		result = resp
+	}
+
+	encoder := json.NewEncoder(w)
+
+	for _, n := range e.events {
+		if err := encoder.Encode(n); err != nil {
+			return err
+		}
+	}
+
+	e.events = nil
+
+	if result == nil {
+		return nil
+	}
+
+	return encoder.Encode(result)
+}
+
+// handle runs a single request and returns its response, false is returned for notifications.
+func (e *Engine) handle(raw json.RawMessage) (response, bool) {
+	var probe any
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return errorResponse(nil, newError(codeParseError, err.Error())), true
+	}
+
+	var req request
+
+	if _, ok := probe.(map[string]any); !ok {
+		return errorResponse(nil, newError(codeInvalidRequest, "request should be an object")), true
+	}
+
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != jsonrpcVersion || req.Method == "" ||
+		!validID(req.ID) {
+		return errorResponse(nil, newError(codeInvalidRequest, "invalid JSON-RPC 2.0 request")), true
+	}
+
+	result, err := e.call(req.Method, req.Params)
+
+	if req.ID == nil {
+		return response{}, false
+	}
+
+	if err != nil {
+		return errorResponse(req.ID, toRPCError(err)), true
+	}
+
+	return response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: result}, true
+}
+
+// validID checks the ID is a string, a number or null.
+func validID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
+
+func errorResponse(id json.RawMessage, err *rpcError) response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return response{JSONRPC: jsonrpcVersion, ID: id, Error: err}
+}
+
+// setGame replaces the game, its events are queued as notifications.
+func (e *Engine) setGame(g *game.Game) {
+	e.unsubscribe()
+
+	e.game = g
+	e.unsubscribe = g.Subscribe(func(ev game.Event) {
+		if params, ok := eventParams(g, ev); ok {
+			e.events = append(e.events, notification{JSONRPC: jsonrpcVersion, Method: "event", Params: params})
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package engine_test
+
+import (
+	"bytes"
+	"proxx/internal/proxx/engine"
+	"proxx/internal/proxx/game"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Serve(t *testing.T) {
+	script := []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "new", "params": {"preset": "tiny", "seed": 1}}`,
+		``,
+		`{"jsonrpc": "2.0", "id": 2, "method": "flag", "params": {"row": 0, "col": 0}}`,
+		// the last line may have no line break
+		`{"jsonrpc": "2.0", "id": 3, "method": "resign"}`,
+	}
+
+	var out bytes.Buffer
+
+	e := engine.New(engine.Config{CustomPresets: []game.Preset{{Name: "tiny", NumRows: 2, NumCols: 2, NumBlackHoles: 1}}})
+	require.NoError(t, e.Serve(strings.NewReader(strings.Join(script, "\n")), &out))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 7)
+
+	assert.Contains(t, lines[0], `"id":1,"result":{"rows":2,"cols":2,"black_holes":1`)
+	assert.Contains(t, lines[1], `"method":"event","params":{"type":"game_started"`)
+	assert.Contains(t, lines[2], `"params":{"type":"cell_flagged","cells":[{"row":0,"col":0,"value":"F"}]`)
+	assert.Contains(t, lines[3], `"id":2,"result":{`)
+	assert.Contains(t, lines[4], `"params":{"type":"cells_opened"`)
+	assert.Contains(t, lines[5], `"params":{"type":"game_resigned"`)
+	assert.Contains(t, lines[6], `"id":3,"result":{`)
+	assert.Contains(t, lines[6], `"status":"lost","resigned":true`)
+}
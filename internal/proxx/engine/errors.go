@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"errors"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+)
+
+// Codes of the errors defined by JSON-RPC 2.0.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+var (
+	ErrNoGame        = errors.New("there is no game, start one with the \"new\" method")
+	ErrInvalidConfig = errors.New("invalid game configuration")
+	ErrFile          = errors.New("file error")
+	ErrInvalidSave   = errors.New("invalid saved game")
+
+	errMethodNotFound = errors.New("method not found")
+	errInvalidParams  = errors.New("invalid params")
+)
+
+// errorCodes maps errors to the codes of JSON-RPC errors and the reasons front ends can rely on,
+// the first match wins. Errors of the game have positive codes.
+var errorCodes = []struct {
+	err    error
+	code   int
+	reason string
+}{
+	{err: errMethodNotFound, code: codeMethodNotFound, reason: "method_not_found"},
+	{err: errInvalidParams, code: codeInvalidParams, reason: "invalid_params"},
+	{err: api.ErrInvalidRequest, code: codeInvalidParams, reason: "invalid_params"},
+	{err: ErrNoGame, code: 1, reason: "no_game"},
+	{err: game.ErrUnknownPreset, code: 2, reason: "unknown_preset"},
+	{err: game.ErrUnknownLocator, code: 3, reason: "unknown_locator"},
+	{err: ErrInvalidConfig, code: 4, reason: "invalid_config"},
+	{err: api.ErrInvalidConfig, code: 4, reason: "invalid_config"},
+	{err: game.ErrCellPositionIsOutsideBoard, code: 5, reason: "position_outside_board"},
+	{err: game.ErrGameOver, code: 6, reason: "game_over"},
+	{err: game.ErrCellAlreadyOpen, code: 7, reason: "cell_already_open"},
+	{err: game.ErrCellFlagged, code: 8, reason: "cell_flagged"},
+	{err: game.ErrCellNotOpen, code: 9, reason: "cell_not_open"},
+	{err: game.ErrChordNotMatching, code: 10, reason: "chord_not_matching"},
+	{err: game.ErrNothingToUndo, code: 11, reason: "nothing_to_undo"},
+	{err: game.ErrNothingToRedo, code: 12, reason: "nothing_to_redo"},
+	{err: ErrFile, code: 13, reason: "file_error"},
+	{err: ErrInvalidSave, code: 14, reason: "invalid_save"},
+	{err: game.ErrUnsupportedSaveVersion, code: 14, reason: "invalid_save"},
+	{err: game.ErrInconsistentSave, code: 14, reason: "invalid_save"},
+}
+
+// rpcError is the error object of a response.
+type rpcError struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *errorData `json:"data,omitempty"`
+}
+
+type errorData struct {
+	Reason string `json:"reason"`
+}
+
+func newError(code int, message string) *rpcError {
+	return &rpcError{Code: code, Message: message}
+}
+
+// toRPCError returns the error object of the error. Errors that don't map to anything are internal errors.
+func toRPCError(err error) *rpcError {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return &rpcError{Code: c.code, Message: err.Error(), Data: &errorData{Reason: c.reason}}
+		}
+	}
+
+	return &rpcError{Code: codeInternalError, Message: err.Error()}
+}
+
+// isSaveError checks whether the error is about the content of a saved game rather than the file.
+func isSaveError(err error) bool {
+	return errors.Is(err, game.ErrUnsupportedSaveVersion) || errors.Is(err, game.ErrInconsistentSave)
+}
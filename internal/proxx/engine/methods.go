@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+)
+
+type positionParams struct {
+	Row *int `json:"row"`
+	Col *int `json:"col"`
+}
+
+type fileParams struct {
+	Path string          `json:"path"`
+	Game json.RawMessage `json:"game"`
+}
+
+type openResult struct {
+	Revealed []api.Cell `json:"revealed"`
+	State    api.Game   `json:"state"`
+}
+
+type hintResult struct {
+	Row         int     `json:"row"`
+	Col         int     `json:"col"`
+	BlackHole   bool    `json:"black_hole"`
+	Certain     bool    `json:"certain"`
+	Probability float64 `json:"probability"`
+	Reason      string  `json:"reason"`
+}
+
+type saveResult struct {
+	Path string          `json:"path,omitempty"`
+	Game json.RawMessage `json:"game,omitempty"`
+}
+
+// eventResult is the parameter of an "event" notification. Cells hold the values of the changed cells,
+// an undone move sends the whole state.
+type eventResult struct {
+	Type      string     `json:"type"`
+	Cells     []api.Cell `json:"cells,omitempty"`
+	Exploded  *[2]int    `json:"exploded,omitempty"`
+	State     *api.Game  `json:"state,omitempty"`
+	ElapsedMs int64      `json:"elapsed_ms"`
+}
+
+// notation writes the positions in the reasons of hints, positions of the engine are zero-based.
+var notation = command.Notation{ZeroBased: true}
+
+// method is a handler of requests, most of the methods need a game.
+type method struct {
+	handle   func(e *Engine, params json.RawMessage) (any, error)
+	needGame bool
+}
+
+var methods = map[string]method{
+	"new":  {handle: (*Engine).newGame},
+	"load": {handle: (*Engine).load},
+	"open": {handle: func(e *Engine, params json.RawMessage) (any, error) { return e.move("open", params) },
+		needGame: true},
+	"flag": {handle: func(e *Engine, params json.RawMessage) (any, error) { return e.move("flag", params) },
+		needGame: true},
+	"chord": {handle: func(e *Engine, params json.RawMessage) (any, error) { return e.move("chord", params) },
+		needGame: true},
+	"state": {handle: func(e *Engine, _ json.RawMessage) (any, error) { return api.NewGame(e.game), nil },
+		needGame: true},
+	"hint": {handle: (*Engine).hint, needGame: true},
+	"undo": {handle: func(e *Engine, _ json.RawMessage) (any, error) { return e.stateAfter(e.game.Undo()) },
+		needGame: true},
+	"redo": {handle: func(e *Engine, _ json.RawMessage) (any, error) { return e.stateAfter(e.game.Redo()) },
+		needGame: true},
+	"resign": {handle: func(e *Engine, _ json.RawMessage) (any, error) { return e.stateAfter(e.game.Resign()) },
+		needGame: true},
+	"save": {handle: (*Engine).save, needGame: true},
+}
+
+// call runs the method with the parameters.
+func (e *Engine) call(name string, params json.RawMessage) (any, error) {
+	m, ok := methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errMethodNotFound, name)
+	}
+
+	if m.needGame && e.game == nil {
+		return nil, ErrNoGame
+	}
+
+	return m.handle(e, params)
+}
+
+// decodeParams decodes the parameters, unknown fields are rejected. Parameters may be omitted.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidParams, err)
+	}
+
+	return nil
+}
+
+func (e *Engine) newGame(params json.RawMessage) (any, error) {
+	var p api.CreateRequest
+
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	cfg, err := p.Config(e.cfg.CustomPresets, e.cfg.MaxCells)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := game.NewGame(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	e.setGame(g)
+
+	return api.NewGame(g), nil
+}
+
+func (e *Engine) move(method string, params json.RawMessage) (any, error) {
+	var p positionParams
+
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Row == nil || p.Col == nil {
+		return nil, fmt.Errorf("%w: row and col are required", errInvalidParams)
+	}
+
+	var (
+		result game.OpenResult
+		err    error
+	)
+
+	switch method {
+	case "open":
+		result, err = e.game.OpenCell(*p.Row, *p.Col)
+	case "chord":
+		result, err = e.game.Chord(*p.Row, *p.Col)
+	default:
+		return e.stateAfter(e.game.ToggleFlag(*p.Row, *p.Col))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return openResult{Revealed: api.Revealed(result), State: api.NewGame(e.game)}, nil
+}
+
+func (e *Engine) stateAfter(err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return api.NewGame(e.game), nil
+}
+
+func (e *Engine) hint(json.RawMessage) (any, error) {
+	h, err := e.game.Hint()
+	if err != nil {
+		return nil, err
+	}
+
+	return hintResult{Row: h.Position.Row, Col: h.Position.Col, BlackHole: h.BlackHole, Certain: h.Certain,
+		Probability: h.Probability, Reason: h.Reason.Explain(notation.FormatPosition)}, nil
+}
+
+func (e *Engine) save(params json.RawMessage) (any, error) {
+	var p fileParams
+
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Game != nil {
+		return nil, fmt.Errorf("%w: only the path can be given", errInvalidParams)
+	}
+
+	if p.Path != "" {
+		if err := command.SaveFile(e.game, p.Path); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFile, err)
+		}
+
+		return saveResult{Path: p.Path}, nil
+	}
+
+	var buf bytes.Buffer
+
+	if err := e.game.Save(&buf); err != nil {
+		return nil, err
+	}
+
+	return saveResult{Game: bytes.TrimSpace(buf.Bytes())}, nil
+}
+
+func (e *Engine) load(params json.RawMessage) (any, error) {
+	var p fileParams
+
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if (p.Path == "") == (p.Game == nil) {
+		return nil, fmt.Errorf("%w: either the path or the game should be given", errInvalidParams)
+	}
+
+	var r io.Reader = bytes.NewReader(p.Game)
+
+	if p.Path != "" {
+		f, err := os.Open(p.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFile, err)
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	g, err := game.Load(r)
+	if err != nil {
+		if !isSaveError(err) {
+			err = fmt.Errorf("%w: %w", ErrInvalidSave, err)
+		}
+
+		return nil, err
+	}
+
+	e.setGame(g)
+
+	return api.NewGame(g), nil
+}
+
+// eventParams returns the notification of the event, clock events aren't sent.
+func eventParams(g *game.Game, e game.Event) (eventResult, bool) {
+	ev, ok := api.NewEvent(g, e)
+	if !ok {
+		return eventResult{}, false
+	}
+
+	return eventResult{Type: ev.Type, Cells: ev.Cells, Exploded: ev.Exploded, State: ev.Game, ElapsedMs: ev.ElapsedMs}, true
+}
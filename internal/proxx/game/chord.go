@@ -0,0 +1,61 @@
+package game
+
+import (
+	"errors"
+	"proxx/internal/proxx/board"
+)
+
+var (
+	ErrCellNotOpen      = errors.New("cell isn't open")
+	ErrChordNotMatching = errors.New("number of flags around the cell doesn't match its clue")
+)
+
+// Chord opens all the closed cells without flags around the open clue at the specified position.
+// The number of flags around the clue must be equal to the clue. If a flag is misplaced,
+// the chord opens a black hole and the game is lost.
+// Returns an error if the position isn't within the board, the game is over, the cell isn't open
+// or the number of flags doesn't match the clue. A chord that has nothing to open isn't counted as a move.
+func (g *Game) Chord(row int, col int) (OpenResult, error) {
+	if !g.board.ValidCellPosition(row, col) {
+		return OpenResult{}, ErrCellPositionIsOutsideBoard
+	}
+
+	if g.IsOver() {
+		return OpenResult{}, ErrGameOver
+	}
+
+	cell := g.board.CellAt(row, col)
+
+	if !cell.IsOpen() {
+		return OpenResult{}, ErrCellNotOpen
+	}
+
+	var (
+		flags  int
+		closed []board.Position
+	)
+
+	for _, p := range g.board.GetSurroundingCellPositions(row, col) {
+		c := g.board.CellAt(p.Row, p.Col)
+
+		switch {
+		case c.IsFlagged():
+			flags++
+		case !c.IsOpen():
+			closed = append(closed, p)
+		}
+	}
+
+	if clue, _ := cell.Value().Clue(); flags != clue {
+		return OpenResult{}, ErrChordNotMatching
+	}
+
+	if len(closed) == 0 {
+		return OpenResult{Status: g.Status()}, nil
+	}
+
+	g.startOrResumeClock()
+	g.record(MoveChord, board.Position{Row: row, Col: col})
+
+	return g.openCells(closed), nil
+}
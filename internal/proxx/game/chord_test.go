@@ -0,0 +1,112 @@
+package game_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/testhelpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Chord(t *testing.T) {
+	gameCfg := game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	}
+
+	t.Run("Chord opens the cells around the satisfied clue", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+		require.NoError(t, g.ToggleFlag(1, 2))
+
+		result, err := g.Chord(0, 2)
+		require.NoError(t, err)
+
+		assert.False(t, result.HitBlackHole)
+		assert.Equal(t, game.StatusInProgress, result.Status)
+		assert.ElementsMatch(t, []game.RevealedCell{
+			{Position: board.Position{Row: 0, Col: 1}, Value: "1"},
+			{Position: board.Position{Row: 1, Col: 1}, Value: "2"},
+		}, result.Revealed)
+
+		expectedState := [][]board.CellValue{
+			{"?", "1", "1"},
+			{"?", "2", "F"},
+			{"?", "?", "?"},
+		}
+		testhelpers.EqualBoardStates(t, expectedState, g.BoardState())
+
+		history := g.History()
+		require.Len(t, history, 3)
+		assert.Equal(t, game.MoveChord, history[2].Kind)
+	})
+
+	t.Run("Chord with a misplaced flag loses the game", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+		require.NoError(t, g.ToggleFlag(0, 1))
+
+		result, err := g.Chord(0, 2)
+		require.NoError(t, err)
+
+		assert.True(t, result.HitBlackHole)
+		assert.Equal(t, game.StatusLost, result.Status)
+		assert.True(t, g.IsOver())
+	})
+
+	t.Run("Number of flags doesn't match the clue", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+
+		_, err = g.Chord(0, 2)
+		assert.ErrorIs(t, err, game.ErrChordNotMatching)
+	})
+
+	t.Run("Chord on a closed cell", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.Chord(0, 0)
+		assert.ErrorIs(t, err, game.ErrCellNotOpen)
+
+		_, err = g.Chord(5, 0)
+		assert.ErrorIs(t, err, game.ErrCellPositionIsOutsideBoard)
+	})
+
+	t.Run("Chord with nothing to open isn't a move", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 0)
+		require.NoError(t, err)
+
+		result, err := g.Chord(0, 0)
+		require.NoError(t, err)
+
+		assert.Empty(t, result.Revealed)
+		assert.Len(t, g.History(), 1)
+	})
+}
@@ -0,0 +1,49 @@
+package game
+
+import "time"
+
+// clock measures the time a player spends on a game.
+// It starts with the first move, can be paused and stops when the game is over.
+type clock struct {
+	elapsed   time.Duration
+	resumedAt time.Time
+	started   bool
+	running   bool
+}
+
+func (c *clock) start(now time.Time) {
+	c.started = true
+	c.running = true
+	c.resumedAt = now
+}
+
+func (c *clock) pause(now time.Time) {
+	if !c.running {
+		return
+	}
+
+	c.elapsed += now.Sub(c.resumedAt)
+	c.running = false
+}
+
+func (c *clock) resume(now time.Time) {
+	if !c.started || c.running {
+		return
+	}
+
+	c.running = true
+	c.resumedAt = now
+}
+
+func (c *clock) read(now time.Time) time.Duration {
+	if c.running {
+		return c.elapsed + now.Sub(c.resumedAt)
+	}
+
+	return c.elapsed
+}
+
+// isPaused returns true if the clock was started and then paused.
+func (c *clock) isPaused() bool {
+	return c.started && !c.running
+}
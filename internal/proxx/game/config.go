@@ -1,11 +1,19 @@
 package game
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"proxx/internal/proxx/board"
+)
 
 var (
-	ErrTooManyBlackHoles           = errors.New("too many black holes: at least one cell should be free from them")
-	ErrNoBlackHolesProvided        = errors.New("too few black holes: at least one black hole required")
-	ErrBlackHoleLocatorNotProvided = errors.New("black hole locator wasn't provided")
+	ErrTooManyBlackHoles             = errors.New("too many black holes: at least one cell should be free from them")
+	ErrTooManyBlackHolesForSafeStart = errors.New("too many black holes: a safe first click needs at least two free cells")
+	ErrNoBlackHolesProvided          = errors.New("too few black holes: at least one black hole required")
+	ErrBlackHoleLocatorNotProvided   = errors.New("black hole locator wasn't provided")
+	ErrInvalidBlackHoleDensity       = errors.New("black hole density should be a percentage greater than 0 and less than 100")
+	ErrBlackHoleCountAndDensity      = errors.New("either the number of black holes or their density should be provided, not both")
 )
 
 // Config represents a configuration for the Proxx game.
@@ -13,19 +21,47 @@ var (
 // Currently, the UniformBlackHoleLocator type is provided to uniformly distribute black holes.
 // At least one cell should be left for a clue to successfully create a new game.
 // At least 1 black hole should be present to successfully create a new game.
+// If FirstClickSafe is true, a black hole under the first opened cell is moved away,
+// so at least two cells should be left free from black holes.
+// BlackHoleDensity is the percentage of cells occupied by black holes, it may be provided
+// instead of NumBlackHoles. A new game turns it into the number of black holes.
 type Config struct {
 	NumRows          int
 	NumCols          int
 	NumBlackHoles    int
+	BlackHoleDensity float64
 	BlackHoleLocator BlackHoleLocator
+	FirstClickSafe   bool
 }
 
 func (cfg Config) Validate() error {
-	if cfg.NumBlackHoles >= cfg.NumRows*cfg.NumCols {
-		return ErrTooManyBlackHoles
+	if err := (board.Config{NumRows: cfg.NumRows, NumCols: cfg.NumCols}).Validate(); err != nil {
+		return err
+	}
+
+	if cfg.BlackHoleDensity != 0 {
+		if cfg.NumBlackHoles != 0 {
+			return ErrBlackHoleCountAndDensity
+		}
+
+		if math.IsNaN(cfg.BlackHoleDensity) || math.IsInf(cfg.BlackHoleDensity, 0) ||
+			cfg.BlackHoleDensity < 0 || cfg.BlackHoleDensity >= 100 {
+			return ErrInvalidBlackHoleDensity
+		}
+	}
+
+	numBlackHoles := cfg.BlackHoleCount()
+	numCells := cfg.NumRows * cfg.NumCols
+
+	if numBlackHoles >= numCells {
+		return cfg.densityError(ErrTooManyBlackHoles)
 	}
 
-	if cfg.NumBlackHoles < 1 {
+	if cfg.FirstClickSafe && numBlackHoles > numCells-2 {
+		return cfg.densityError(ErrTooManyBlackHolesForSafeStart)
+	}
+
+	if numBlackHoles < 1 {
 		return ErrNoBlackHolesProvided
 	}
 
@@ -35,3 +71,28 @@ func (cfg Config) Validate() error {
 
 	return nil
 }
+
+// BlackHoleCount returns the number of black holes on the board: NumBlackHoles or
+// the number of cells given by BlackHoleDensity rounded to the nearest integer, but at least one.
+func (cfg Config) BlackHoleCount() int {
+	if cfg.BlackHoleDensity <= 0 {
+		return cfg.NumBlackHoles
+	}
+
+	count := int(math.Round(float64(cfg.NumRows*cfg.NumCols) * cfg.BlackHoleDensity / 100))
+	if count < 1 {
+		return 1
+	}
+
+	return count
+}
+
+// densityError explains which number of black holes the density gives.
+func (cfg Config) densityError(err error) error {
+	if cfg.BlackHoleDensity <= 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w: density %g%% gives %d black holes on %d cells",
+		err, cfg.BlackHoleDensity, cfg.BlackHoleCount(), cfg.NumRows*cfg.NumCols)
+}
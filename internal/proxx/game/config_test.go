@@ -1,10 +1,12 @@
 package game_test
 
 import (
+	"math"
 	"proxx/internal/proxx/game"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -26,10 +28,36 @@ func TestConfig_Validate(t *testing.T) {
 			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 30, BlackHoleLocator: bhLocator}},
 		{name: "Black hole locator missing", errExpected: true,
 			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 30}},
+		{name: "Zero number of rows", errExpected: true,
+			cfg: game.Config{NumRows: 0, NumCols: 5, NumBlackHoles: 1, BlackHoleLocator: bhLocator}},
+		{name: "Negative dimensions", errExpected: true,
+			cfg: game.Config{NumRows: -5, NumCols: -5, NumBlackHoles: 1, BlackHoleLocator: bhLocator}},
+		{name: "Too many black holes for a safe first click", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 24, BlackHoleLocator: bhLocator, FirstClickSafe: true}},
+		{name: "Valid configuration: safe first click", errExpected: false,
+			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 23, BlackHoleLocator: bhLocator, FirstClickSafe: true}},
 		{name: "Valid configuration: one cell for a clue", errExpected: false,
 			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 24, BlackHoleLocator: bhLocator}},
 		{name: "Valid configuration: enough space for multiple black holes", errExpected: false,
 			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 12, BlackHoleLocator: bhLocator}},
+		{name: "Black holes given by count and density", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, NumBlackHoles: 5, BlackHoleDensity: 20, BlackHoleLocator: bhLocator}},
+		{name: "Negative density", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: -20, BlackHoleLocator: bhLocator}},
+		{name: "Density is not a number", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: math.NaN(), BlackHoleLocator: bhLocator}},
+		{name: "Infinite density", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: math.Inf(1), BlackHoleLocator: bhLocator}},
+		{name: "Density of 100%", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: 100, BlackHoleLocator: bhLocator}},
+		{name: "Density leaves no free cell", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: 99, BlackHoleLocator: bhLocator}},
+		{name: "Density is too high for a safe first click", errExpected: true,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: 95, BlackHoleLocator: bhLocator, FirstClickSafe: true}},
+		{name: "Valid configuration: density", errExpected: false,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: 20, BlackHoleLocator: bhLocator}},
+		{name: "Valid configuration: tiny density gives one black hole", errExpected: false,
+			cfg: game.Config{NumRows: 5, NumCols: 5, BlackHoleDensity: 0.1, BlackHoleLocator: bhLocator}},
 	}
 
 	for _, tc := range testCases {
@@ -46,3 +74,37 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_BlackHoleCount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      game.Config
+		expected int
+	}{
+		{name: "Number of black holes", cfg: game.Config{NumRows: 9, NumCols: 9, NumBlackHoles: 10}, expected: 10},
+		{name: "Density", cfg: game.Config{NumRows: 16, NumCols: 30, BlackHoleDensity: 20.625}, expected: 99},
+		{name: "Density is rounded", cfg: game.Config{NumRows: 9, NumCols: 9, BlackHoleDensity: 12.5}, expected: 10},
+		{name: "At least one black hole", cfg: game.Config{NumRows: 3, NumCols: 3, BlackHoleDensity: 1}, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.cfg.BlackHoleCount())
+		})
+	}
+
+	t.Run("New game turns density into the number of black holes", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{NumRows: 16, NumCols: 30, BlackHoleDensity: 20.625,
+			BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(1)})
+		require.NoError(t, err)
+
+		assert.Equal(t, 99, g.NumBlackHoles())
+		assert.Len(t, g.BlackHoles(), 99)
+		assert.NoError(t, g.Config().Validate())
+	})
+}
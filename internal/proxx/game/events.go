@@ -0,0 +1,187 @@
+package game
+
+import (
+	"proxx/internal/proxx/board"
+	"sync"
+	"time"
+)
+
+// Event is implemented by all the events emitted by a game.
+type Event interface {
+	event()
+}
+
+// GameStarted is emitted on the first move of a game, when the clock starts.
+type GameStarted struct{}
+
+// CellOpened is emitted when cells are opened. Positions lists all the cells opened by the move:
+// the chosen cell goes first, then the cells opened by the cascade.
+type CellOpened struct {
+	Positions []board.Position
+}
+
+// CellFlagged is emitted when a flag is put on a cell (Flagged is true) or removed from it.
+type CellFlagged struct {
+	Position board.Position
+	Flagged  bool
+}
+
+// GameWon is emitted when all the cells without black holes are opened.
+type GameWon struct {
+	Elapsed time.Duration
+}
+
+// GameLost is emitted when a player opens a cell with a black hole at Position.
+type GameLost struct {
+	Position board.Position
+	Elapsed  time.Duration
+}
+
+// GameResigned is emitted when the player gives the game up.
+type GameResigned struct {
+	Elapsed time.Duration
+}
+
+// MoveUndone is emitted when the move is taken back. The board is restored to the state before the move,
+// so it should be read again.
+type MoveUndone struct {
+	Move Move
+}
+
+// ClockPaused is emitted when the game's clock is paused.
+type ClockPaused struct {
+	Elapsed time.Duration
+}
+
+// ClockResumed is emitted when the game's clock is resumed.
+type ClockResumed struct {
+	Elapsed time.Duration
+}
+
+func (GameStarted) event()  {}
+func (CellOpened) event()   {}
+func (CellFlagged) event()  {}
+func (GameWon) event()      {}
+func (GameLost) event()     {}
+func (GameResigned) event() {}
+func (MoveUndone) event()   {}
+func (ClockPaused) event()  {}
+func (ClockResumed) event() {}
+
+// subscriber is a single subscription to the game's events.
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe registers fn to be called on every game event.
+// Events are delivered synchronously, in the order they happen, before the game method that caused them returns.
+// The returned function cancels the subscription.
+func (g *Game) Subscribe(fn func(Event)) (unsubscribe func()) {
+	g.lastSubscriberID++
+	id := g.lastSubscriberID
+
+	g.subscribers = append(g.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		for i, s := range g.subscribers {
+			if s.id == id {
+				g.subscribers = append(g.subscribers[:i:i], g.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeChan returns a channel that receives the game events in order.
+// Events are queued, so a slow reader never blocks the game.
+// The returned function cancels the subscription, drops the events that aren't read yet and closes the channel.
+func (g *Game) SubscribeChan() (<-chan Event, func()) {
+	q := newEventQueue()
+	unsubscribe := g.Subscribe(q.push)
+
+	return q.out, func() {
+		unsubscribe()
+		q.close()
+	}
+}
+
+func (g *Game) emit(e Event) {
+	subscribers := g.subscribers
+
+	for _, s := range subscribers {
+		s.fn(e)
+	}
+}
+
+// eventQueue is an unbounded queue of events that feeds a channel.
+// done is closed with the queue, so the feeding goroutine never waits for a reader that has left.
+type eventQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	events []Event
+	closed bool
+	out    chan Event
+	done   chan struct{}
+}
+
+func newEventQueue() *eventQueue {
+	q := &eventQueue{out: make(chan Event), done: make(chan struct{})}
+	q.cond = sync.NewCond(&q.mu)
+
+	go q.run()
+
+	return q
+}
+
+func (q *eventQueue) push(e Event) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.events = append(q.events, e)
+	q.cond.Signal()
+}
+
+func (q *eventQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.closed = true
+	q.events = nil
+	close(q.done)
+	q.cond.Signal()
+}
+
+func (q *eventQueue) run() {
+	defer close(q.out)
+
+	for {
+		q.mu.Lock()
+		for len(q.events) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+
+		e := q.events[0]
+		q.events = q.events[1:]
+		q.mu.Unlock()
+
+		select {
+		case q.out <- e:
+		case <-q.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package game_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Subscribe(t *testing.T) {
+	gameCfg := game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	}
+
+	t.Run("Win a game", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		var events []game.Event
+		g.Subscribe(func(e game.Event) {
+			events = append(events, e)
+		})
+
+		require.NoError(t, g.ToggleFlag(1, 2))
+		_, err = g.OpenCell(1, 0)
+		require.NoError(t, err)
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+
+		require.Len(t, events, 5)
+		assert.IsType(t, game.GameStarted{}, events[0])
+		assert.EqualValues(t, game.CellFlagged{Position: board.Position{Row: 1, Col: 2}, Flagged: true}, events[1])
+		require.IsType(t, game.CellOpened{}, events[2])
+		opened := events[2].(game.CellOpened).Positions
+		assert.EqualValues(t, board.Position{Row: 1, Col: 0}, opened[0])
+		assert.ElementsMatch(t, []board.Position{
+			{Row: 1, Col: 0}, {Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 1, Col: 1}, {Row: 2, Col: 0}, {Row: 2, Col: 1},
+		}, opened)
+		assert.EqualValues(t, game.CellOpened{Positions: []board.Position{{Row: 0, Col: 2}}}, events[3])
+		assert.IsType(t, game.GameWon{}, events[4])
+	})
+
+	t.Run("Lose a game", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		var events []game.Event
+		g.Subscribe(func(e game.Event) {
+			events = append(events, e)
+		})
+
+		_, err = g.OpenCell(2, 2)
+		require.NoError(t, err)
+
+		require.Len(t, events, 3)
+		assert.IsType(t, game.GameStarted{}, events[0])
+		assert.EqualValues(t, game.CellOpened{Positions: []board.Position{{Row: 2, Col: 2}, {Row: 1, Col: 2}}}, events[1])
+		require.IsType(t, game.GameLost{}, events[2])
+		assert.EqualValues(t, board.Position{Row: 2, Col: 2}, events[2].(game.GameLost).Position)
+	})
+
+	t.Run("Pause and resume the clock", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		var events []game.Event
+		unsubscribe := g.Subscribe(func(e game.Event) {
+			events = append(events, e)
+		})
+
+		// the clock isn't started yet
+		g.Pause()
+		assert.False(t, g.IsPaused())
+
+		_, err = g.OpenCell(1, 1)
+		require.NoError(t, err)
+		g.Pause()
+		assert.True(t, g.IsPaused())
+
+		// any move resumes the clock
+		require.NoError(t, g.ToggleFlag(1, 2))
+		assert.False(t, g.IsPaused())
+
+		unsubscribe()
+		g.Pause()
+
+		require.Len(t, events, 5)
+		assert.IsType(t, game.GameStarted{}, events[0])
+		assert.IsType(t, game.CellOpened{}, events[1])
+		assert.IsType(t, game.ClockPaused{}, events[2])
+		assert.IsType(t, game.ClockResumed{}, events[3])
+		assert.IsType(t, game.CellFlagged{}, events[4])
+	})
+
+	t.Run("Flagged cell stays closed", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		require.NoError(t, g.ToggleFlag(0, 0))
+		_, err = g.OpenCell(0, 0)
+		require.ErrorIs(t, err, game.ErrCellFlagged)
+		_, err = g.OpenCell(2, 0)
+		require.NoError(t, err)
+
+		expectedState := [][]board.CellValue{
+			{"F", "1", "?"},
+			{"0", "2", "?"},
+			{"0", "2", "?"},
+		}
+
+		assert.EqualValues(t, expectedState, g.BoardState())
+	})
+}
+
+func TestGame_SubscribeChan(t *testing.T) {
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	})
+	require.NoError(t, err)
+
+	events, unsubscribe := g.SubscribeChan()
+
+	_, err = g.OpenCell(1, 1)
+	require.NoError(t, err)
+	require.NoError(t, g.ToggleFlag(2, 2))
+
+	received := []game.Event{<-events, <-events, <-events}
+
+	assert.IsType(t, game.GameStarted{}, received[0])
+	assert.IsType(t, game.CellOpened{}, received[1])
+	assert.IsType(t, game.CellFlagged{}, received[2])
+
+	unsubscribe()
+
+	_, ok := <-events
+	assert.False(t, ok, "the channel should be closed")
+}
+
+func TestGame_SubscribeChan_UnsubscribeWithoutReading(t *testing.T) {
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	})
+	require.NoError(t, err)
+
+	// the game may start goroutines of its own on the first move
+	_, err = g.OpenCell(1, 1)
+	require.NoError(t, err)
+
+	before := runtime.NumGoroutine()
+
+	_, unsubscribe := g.SubscribeChan()
+
+	require.NoError(t, g.ToggleFlag(2, 2))
+	require.NoError(t, g.ToggleFlag(2, 2))
+
+	// nobody reads the events, the goroutine feeding the channel exits anyway
+	unsubscribe()
+	unsubscribe()
+
+	// assert.Eventually runs the condition in goroutines of its own, so the goroutines are counted here
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before, "the goroutine feeding the channel should exit")
+}
@@ -0,0 +1,30 @@
+package game
+
+import "proxx/internal/proxx/board"
+
+// FixedLocatorName is the name of FixedBlackHoleLocator.
+const FixedLocatorName = "fixed"
+
+// FixedBlackHoleLocator places black holes at the predefined positions.
+// It's used to restore saved games and to replay recorded ones.
+type FixedBlackHoleLocator struct {
+	positions []board.Position
+}
+
+// NewFixedBlackHoleLocator returns new FixedBlackHoleLocator object.
+func NewFixedBlackHoleLocator(positions []board.Position) *FixedBlackHoleLocator {
+	return &FixedBlackHoleLocator{positions: positions}
+}
+
+// Name returns the name of the locator.
+func (l FixedBlackHoleLocator) Name() string {
+	return FixedLocatorName
+}
+
+// LocateBlackHolesOnBoard returns the predefined positions regardless of the board's dimensions.
+func (l FixedBlackHoleLocator) LocateBlackHolesOnBoard(_ int, _ int, _ int) []board.Position {
+	positions := make([]board.Position, len(l.positions))
+	copy(positions, l.positions)
+
+	return positions
+}
@@ -4,12 +4,17 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"math/rand"
 	"proxx/internal/proxx/board"
+	"time"
 )
 
 var (
 	ErrCellPositionIsOutsideBoard = errors.New("cell position is out of the board's bounds")
 	ErrNumberOfBlackHolesMismatch = errors.New("locator yields different number of black holes than specified via configuration")
+	ErrGameOver                   = errors.New("game is over")
+	ErrCellAlreadyOpen            = errors.New("cell is already open")
+	ErrCellFlagged                = errors.New("cell is flagged")
 )
 
 // Game represents a game.
@@ -18,6 +23,56 @@ type Game struct {
 	cfg    Config
 	isLost bool
 	isWon  bool
+	stats  Stats
+	clock  clock
+	// lostAt is the position of the black hole that ended the game.
+	lostAt board.Position
+	// resigned is true if the player gave up the game, it's lost without hitting a black hole.
+	resigned bool
+	history  []Move
+	// undone holds the undone moves that can be redone, the last undone move goes last.
+	undone  []Move
+	locator locatorInfo
+
+	subscribers      []subscriber
+	lastSubscriberID int
+}
+
+// Status represents a status of a game.
+type Status int
+
+const (
+	StatusInProgress Status = iota
+	StatusWon
+	StatusLost
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusInProgress:
+		return "in progress"
+	case StatusWon:
+		return "won"
+	case StatusLost:
+		return "lost"
+	default:
+		return "unknown"
+	}
+}
+
+// RevealedCell represents a cell opened by a move together with its value.
+type RevealedCell struct {
+	Position board.Position
+	Value    board.CellValue
+}
+
+// OpenResult represents the outcome of opening a cell.
+// Revealed lists all the cells opened by the move: the chosen cell goes first.
+// If a black hole was hit, all the black holes are revealed.
+type OpenResult struct {
+	Revealed     []RevealedCell
+	HitBlackHole bool
+	Status       Status
 }
 
 // BlackHoleLocator is the interface that wraps the LocateBlackHolesOnBoard method.
@@ -29,12 +84,21 @@ type BlackHoleLocator interface {
 	LocateBlackHolesOnBoard(rows int, cols int, bhNum int) []board.Position
 }
 
+// randomLocator is implemented by the locators that place black holes at random.
+// A black hole under a safe first click is moved by the same generator, so a seeded game stays reproducible.
+type randomLocator interface {
+	intn(n int) int
+}
+
 // NewGame creates a new game using the specified configuration.
 func NewGame(cfg Config) (*Game, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	cfg.NumBlackHoles = cfg.BlackHoleCount()
+	cfg.BlackHoleDensity = 0
+
 	blackHoles := cfg.BlackHoleLocator.LocateBlackHolesOnBoard(cfg.NumRows, cfg.NumCols, cfg.NumBlackHoles)
 
 	if len(blackHoles) != cfg.NumBlackHoles {
@@ -48,7 +112,7 @@ func NewGame(cfg Config) (*Game, error) {
 
 	gameBoard.Init(blackHoles)
 
-	return &Game{board: gameBoard, cfg: cfg}, nil
+	return &Game{board: gameBoard, cfg: cfg, locator: describeLocator(cfg.BlackHoleLocator)}, nil
 }
 
 // IsOver checks whether a game is over (won or lost).
@@ -61,44 +125,246 @@ func (g *Game) IsWon() bool {
 	return g.isWon
 }
 
-// OpenCell opens the specified cell. Returns an error if the position isn't within the board.
-func (g *Game) OpenCell(row int, col int) error {
+// Status returns the current status of the game.
+func (g *Game) Status() Status {
+	switch {
+	case g.isWon:
+		return StatusWon
+	case g.isLost:
+		return StatusLost
+	default:
+		return StatusInProgress
+	}
+}
+
+// LostAt returns the position of the black hole that ended the game.
+// ok is false if the game isn't lost or the player resigned.
+func (g *Game) LostAt() (pos board.Position, ok bool) {
+	if !g.isLost || g.resigned {
+		return board.Position{}, false
+	}
+
+	return g.lostAt, true
+}
+
+// OpenCell opens the specified cell and returns the cells revealed by the move.
+// Returns an error if the position isn't within the board, the game is over,
+// the cell is already open or has a flag on it.
+func (g *Game) OpenCell(row int, col int) (OpenResult, error) {
 	if !g.board.ValidCellPosition(row, col) {
-		return ErrCellPositionIsOutsideBoard
+		return OpenResult{}, ErrCellPositionIsOutsideBoard
 	}
 
 	if g.IsOver() {
-		return nil
+		return OpenResult{}, ErrGameOver
 	}
 
 	cell := g.board.CellAt(row, col)
 
 	if cell.IsOpen() {
-		return nil
+		return OpenResult{}, ErrCellAlreadyOpen
 	}
 
-	if cell.IsBlackHole() {
-		g.board.OpenAllBlackHoles()
-		g.isLost = true
-		return nil
+	if cell.IsFlagged() {
+		return OpenResult{}, ErrCellFlagged
+	}
+
+	g.startOrResumeClock()
+
+	pos := board.Position{Row: row, Col: col}
+	g.record(MoveOpen, pos)
+
+	if cell.IsBlackHole() && g.cfg.FirstClickSafe && g.board.OpenedCells() == 0 {
+		g.board.RelocateBlackHole(pos, g.pickCell)
+	}
+
+	return g.openCells([]board.Position{pos}), nil
+}
+
+// pickCell returns a random number in [0, n) for the cell a black hole under a safe first click is moved to.
+// With a locator that doesn't place black holes at random, the global generator is used.
+func (g *Game) pickCell(n int) int {
+	if l, ok := g.cfg.BlackHoleLocator.(randomLocator); ok {
+		return l.intn(n)
 	}
 
-	if cell.IsClue() {
-		cell.MarkAsOpen()
+	return rand.Intn(n)
+}
+
+// openCells opens the specified closed cells together with the cascades started by blank ones.
+// If any of them holds a black hole, the game is lost at the first such cell and all the black holes are revealed.
+func (g *Game) openCells(positions []board.Position) OpenResult {
+	var (
+		opened     []board.Position
+		blackHoles []board.Position
+	)
+
+	for _, pos := range positions {
+		cell := g.board.CellAt(pos.Row, pos.Col)
+
+		switch {
+		case cell.IsOpen():
+			// opened by the cascade of a previous cell
+		case cell.IsBlackHole():
+			blackHoles = append(blackHoles, pos)
+		case cell.IsBlank():
+			opened = append(opened, g.makeCellAndSurroundingCellsOpened(pos.Row, pos.Col)...)
+		default:
+			cell.MarkAsOpen()
+			opened = append(opened, pos)
+		}
 	}
 
-	if cell.IsBlank() {
-		g.makeCellAndSurroundingCellsOpened(row, col)
+	if len(blackHoles) > 0 {
+		g.isLost = true
+		g.lostAt = blackHoles[0]
+		g.clock.pause(time.Now())
+
+		opened = append(opened, blackHoles...)
+		for _, p := range g.board.OpenAllBlackHoles() {
+			if !containsPosition(blackHoles, p) {
+				opened = append(opened, p)
+			}
+		}
+
+		g.emit(CellOpened{Positions: opened})
+		g.emit(GameLost{Position: g.lostAt, Elapsed: g.Elapsed()})
+
+		return g.openResult(opened, true)
 	}
 
+	g.emit(CellOpened{Positions: opened})
+
 	g.isWon = g.board.OpenedCells() == g.maxOpenCells()
 
+	if g.isWon {
+		g.clock.pause(time.Now())
+		g.emit(GameWon{Elapsed: g.Elapsed()})
+	}
+
+	return g.openResult(opened, false)
+}
+
+func containsPosition(positions []board.Position, pos board.Position) bool {
+	for _, p := range positions {
+		if p == pos {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Game) openResult(opened []board.Position, hitBlackHole bool) OpenResult {
+	revealed := make([]RevealedCell, 0, len(opened))
+
+	for _, p := range opened {
+		revealed = append(revealed, RevealedCell{Position: p, Value: g.board.CellAt(p.Row, p.Col).Value()})
+	}
+
+	return OpenResult{Revealed: revealed, HitBlackHole: hitBlackHole, Status: g.Status()}
+}
+
+// Resign gives the game up: the game is lost and all the black holes are revealed.
+// The resignation is recorded in the history, so saved and replayed games end the same way.
+// A resigned game can't be undone. Returns an error if the game is over.
+func (g *Game) Resign() error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+
+	g.record(MoveResign, board.Position{})
+
+	g.isLost = true
+	g.resigned = true
+	g.clock.pause(time.Now())
+
+	g.emit(CellOpened{Positions: g.board.OpenAllBlackHoles()})
+	g.emit(GameResigned{Elapsed: g.Elapsed()})
+
 	return nil
 }
 
+// IsResigned returns true if the player gave the game up.
+func (g *Game) IsResigned() bool {
+	return g.resigned
+}
+
+// ToggleFlag puts a flag on the specified closed cell or removes it.
+// Returns an error if the position isn't within the board, the game is over or the cell is already open.
+func (g *Game) ToggleFlag(row int, col int) error {
+	if !g.board.ValidCellPosition(row, col) {
+		return ErrCellPositionIsOutsideBoard
+	}
+
+	if g.IsOver() {
+		return ErrGameOver
+	}
+
+	cell := g.board.CellAt(row, col)
+
+	if cell.IsOpen() {
+		return ErrCellAlreadyOpen
+	}
+
+	g.startOrResumeClock()
+
+	pos := board.Position{Row: row, Col: col}
+	g.record(MoveToggleFlag, pos)
+
+	cell.ToggleFlag()
+	g.emit(CellFlagged{Position: pos, Flagged: cell.IsFlagged()})
+
+	return nil
+}
+
+// Pause pauses the game's clock. It does nothing if the clock isn't running.
+func (g *Game) Pause() {
+	if g.IsOver() || !g.clock.running {
+		return
+	}
+
+	g.clock.pause(time.Now())
+	g.emit(ClockPaused{Elapsed: g.Elapsed()})
+}
+
+// Resume resumes the paused clock. Any move resumes the clock as well.
+func (g *Game) Resume() {
+	if g.IsOver() || !g.clock.isPaused() {
+		return
+	}
+
+	g.clock.resume(time.Now())
+	g.emit(ClockResumed{Elapsed: g.Elapsed()})
+}
+
+// IsPaused returns true if the game's clock is paused.
+func (g *Game) IsPaused() bool {
+	return !g.IsOver() && g.clock.isPaused()
+}
+
+// Elapsed returns the time spent on the game, pauses excluded.
+func (g *Game) Elapsed() time.Duration {
+	return g.clock.read(time.Now())
+}
+
+// startOrResumeClock starts the clock on the first move and resumes it if the game was paused.
+func (g *Game) startOrResumeClock() {
+	if !g.clock.started {
+		g.clock.start(time.Now())
+		g.emit(GameStarted{})
+		return
+	}
+
+	g.Resume()
+}
+
 // makeCellAndSurroundingCellsOpened marks the specified cell and surrounding cells with clues opened.
-// The effect is also applied to all the surrounding blank cells.
-func (g *Game) makeCellAndSurroundingCellsOpened(row int, col int) {
+// The effect is also applied to all the surrounding blank cells. Flagged cells stay closed.
+// Returns positions of all the opened cells.
+func (g *Game) makeCellAndSurroundingCellsOpened(row int, col int) []board.Position {
+	var opened []board.Position
+
 	queue := list.New()
 	queue.PushBack(board.Position{Row: row, Col: col})
 
@@ -108,7 +374,13 @@ func (g *Game) makeCellAndSurroundingCellsOpened(row int, col int) {
 
 		queue.Remove(el)
 		c := g.board.CellAt(pos.Row, pos.Col)
+
+		if c.IsOpen() {
+			continue
+		}
+
 		c.MarkAsOpen()
+		opened = append(opened, pos)
 
 		if c.IsBlank() {
 			positions := g.board.GetSurroundingCellPositions(pos.Row, pos.Col)
@@ -116,7 +388,7 @@ func (g *Game) makeCellAndSurroundingCellsOpened(row int, col int) {
 			for _, p := range positions {
 				c = g.board.CellAt(p.Row, p.Col)
 
-				if c.IsOpen() {
+				if c.IsOpen() || c.IsFlagged() {
 					continue
 				}
 
@@ -124,17 +396,52 @@ func (g *Game) makeCellAndSurroundingCellsOpened(row int, col int) {
 					queue.PushBack(p)
 				} else {
 					c.MarkAsOpen()
+					opened = append(opened, p)
 				}
 			}
 		}
 	}
+
+	return opened
 }
 
 func (g *Game) maxOpenCells() int {
 	return g.board.TotalNumberOfCells() - g.cfg.NumBlackHoles
 }
 
+// NumBlackHoles returns the number of black holes on the game board.
+func (g *Game) NumBlackHoles() int {
+	return g.cfg.NumBlackHoles
+}
+
+// Config returns the configuration the game was created with.
+func (g *Game) Config() Config {
+	return g.cfg
+}
+
+// BlackHoles returns positions of all the black holes on the board.
+// It reveals the hidden layout: it's intended for recording and analyzing games, never show it to a player.
+func (g *Game) BlackHoles() []board.Position {
+	return g.board.BlackHolePositions()
+}
+
+// Locator returns the name of the locator that placed black holes on the board
+// and its seed if the locator is seeded.
+func (g *Game) Locator() (name string, seed int64, seeded bool) {
+	if g.locator.seed == nil {
+		return g.locator.name, 0, false
+	}
+
+	return g.locator.name, *g.locator.seed, true
+}
+
 // BoardState return the current state of a game board.
 func (g *Game) BoardState() [][]board.CellValue {
 	return g.board.State()
 }
+
+// DebugState returns the values of all the cells, closed ones included.
+// It reveals the hidden layout: it's intended for debugging and analyzing games, never show it to a player.
+func (g *Game) DebugState() [][]board.CellValue {
+	return g.board.DebugState()
+}
@@ -37,16 +37,16 @@ func TestGame_OpenCell(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, g.IsOver())
 
-		err = g.OpenCell(4, 2)
+		_, err = g.OpenCell(4, 2)
 		assert.Error(t, err)
 
-		err = g.OpenCell(1, 5)
+		_, err = g.OpenCell(1, 5)
 		assert.Error(t, err)
 
-		err = g.OpenCell(-1, 3)
+		_, err = g.OpenCell(-1, 3)
 		assert.Error(t, err)
 
-		err = g.OpenCell(2, -2)
+		_, err = g.OpenCell(2, -2)
 		assert.Error(t, err)
 	})
 
@@ -57,7 +57,7 @@ func TestGame_OpenCell(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, g.IsOver())
 
-		err = g.OpenCell(2, 0)
+		_, err = g.OpenCell(2, 0)
 		assert.NoError(t, err)
 
 		expectedState := [][]board.CellValue{
@@ -70,8 +70,8 @@ func TestGame_OpenCell(t *testing.T) {
 		testhelpers.EqualBoardStates(t, expectedState, currentState)
 
 		// open the same (already opened) cell again, nothing should change
-		err = g.OpenCell(2, 0)
-		assert.NoError(t, err)
+		_, err = g.OpenCell(2, 0)
+		assert.ErrorIs(t, err, game.ErrCellAlreadyOpen)
 
 		currentState = g.BoardState()
 		testhelpers.EqualBoardStates(t, expectedState, currentState)
@@ -84,9 +84,12 @@ func TestGame_OpenCell(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, g.IsOver())
 
-		err = g.OpenCell(1, 1)
+		_, err = g.OpenCell(1, 1)
 		assert.NoError(t, err)
 
+		_, ok := g.LostAt()
+		assert.False(t, ok)
+
 		expectedState := [][]board.CellValue{
 			{"?", "?", "?"},
 			{"?", "2", "?"},
@@ -104,7 +107,7 @@ func TestGame_OpenCell(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, g.IsOver())
 
-		err = g.OpenCell(2, 2)
+		_, err = g.OpenCell(2, 2)
 		assert.NoError(t, err)
 
 		expectedState := [][]board.CellValue{
@@ -118,6 +121,10 @@ func TestGame_OpenCell(t *testing.T) {
 
 		assert.True(t, g.IsOver())
 		assert.False(t, g.IsWon())
+
+		lostAt, ok := g.LostAt()
+		assert.True(t, ok)
+		assert.Equal(t, board.Position{Row: 2, Col: 2}, lostAt)
 	})
 
 	t.Run("Win a simple game", func(t *testing.T) {
@@ -127,10 +134,10 @@ func TestGame_OpenCell(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, g.IsOver())
 
-		err = g.OpenCell(1, 0)
+		_, err = g.OpenCell(1, 0)
 		assert.NoError(t, err)
 
-		err = g.OpenCell(0, 2)
+		_, err = g.OpenCell(0, 2)
 		assert.NoError(t, err)
 
 		expectedState := [][]board.CellValue{
@@ -145,4 +152,118 @@ func TestGame_OpenCell(t *testing.T) {
 		assert.True(t, g.IsOver())
 		assert.True(t, g.IsWon())
 	})
+
+	t.Run("Result of opening cells", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		result, err := g.OpenCell(0, 1)
+		require.NoError(t, err)
+
+		assert.EqualValues(t, game.OpenResult{
+			Revealed: []game.RevealedCell{{Position: board.Position{Row: 0, Col: 1}, Value: "1"}},
+			Status:   game.StatusInProgress,
+		}, result)
+
+		result, err = g.OpenCell(2, 2)
+		require.NoError(t, err)
+
+		assert.True(t, result.HitBlackHole)
+		assert.EqualValues(t, game.StatusLost, result.Status)
+		assert.EqualValues(t, []game.RevealedCell{
+			{Position: board.Position{Row: 2, Col: 2}, Value: "H"},
+			{Position: board.Position{Row: 1, Col: 2}, Value: "H"},
+		}, result.Revealed)
+
+		_, err = g.OpenCell(0, 0)
+		assert.ErrorIs(t, err, game.ErrGameOver)
+
+		err = g.ToggleFlag(0, 0)
+		assert.ErrorIs(t, err, game.ErrGameOver)
+	})
+
+	t.Run("Safe first click", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := gameCfg
+		cfg.FirstClickSafe = true
+
+		g, err := game.NewGame(cfg)
+		require.NoError(t, err)
+
+		result, err := g.OpenCell(2, 2)
+		require.NoError(t, err)
+
+		assert.False(t, result.HitBlackHole)
+		assert.False(t, g.IsOver())
+
+		holes := g.BlackHoles()
+		assert.Len(t, holes, 2)
+		assert.Contains(t, holes, board.Position{Row: 1, Col: 2})
+		assert.NotContains(t, holes, board.Position{Row: 2, Col: 2})
+		assert.False(t, g.BoardState()[2][2].IsHidden())
+	})
+
+	t.Run("Safe first click moves the black hole to a random cell", func(t *testing.T) {
+		t.Parallel()
+
+		moved := make(map[board.Position]struct{})
+
+		for seed := int64(1); seed <= 20; seed++ {
+			g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 2, FirstClickSafe: true,
+				BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(seed)})
+			require.NoError(t, err)
+
+			clicked := g.BlackHoles()[0]
+			other := g.BlackHoles()[1]
+
+			_, err = g.OpenCell(clicked.Row, clicked.Col)
+			require.NoError(t, err)
+
+			holes := g.BlackHoles()
+			require.Len(t, holes, 2)
+			require.Contains(t, holes, other)
+			require.NotContains(t, holes, clicked)
+
+			for _, p := range holes {
+				if p != other {
+					moved[p] = struct{}{}
+				}
+			}
+		}
+
+		assert.Greater(t, len(moved), 1)
+	})
+}
+
+func TestGame_Resign(t *testing.T) {
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	var events []game.Event
+	g.Subscribe(func(e game.Event) { events = append(events, e) })
+
+	_, err = g.OpenCell(0, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, g.Resign())
+
+	assert.Equal(t, game.StatusLost, g.Status())
+	assert.True(t, g.IsResigned())
+	assert.IsType(t, game.GameResigned{}, events[len(events)-1])
+
+	_, ok := g.LostAt()
+	assert.False(t, ok)
+
+	testhelpers.EqualBoardStates(t, [][]board.CellValue{
+		{"0", "1", "?"},
+		{"0", "2", "H"},
+		{"0", "2", "H"},
+	}, g.BoardState())
+
+	assert.ErrorIs(t, g.Resign(), game.ErrGameOver)
+	assert.ErrorIs(t, g.Undo(), game.ErrGameOver)
 }
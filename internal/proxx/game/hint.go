@@ -0,0 +1,103 @@
+package game
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/probability"
+	"proxx/internal/proxx/solver"
+	"time"
+)
+
+// hintTimeBudget limits the time spent on the exact calculation of probabilities for a hint.
+const hintTimeBudget = 500 * time.Millisecond
+
+// Hint represents a suggestion for the next move.
+// If Certain is true, the cell at Position is provably a black hole (BlackHole is true)
+// or provably free from it (BlackHole is false) and Reason explains why.
+// Otherwise, Position points to the cell that is least likely to contain a black hole
+// and Probability holds the chance of hitting a black hole there.
+// The positions in the reason are written by the front end in its notation.
+type Hint struct {
+	Position    board.Position
+	BlackHole   bool
+	Certain     bool
+	Probability float64
+	Reason      solver.Reason
+}
+
+// Hint returns a suggestion for the next move based only on the revealed clues.
+// A safe cell is preferred over a black hole. Every hint returned is counted in the game's statistics.
+func (g *Game) Hint() (Hint, error) {
+	if g.IsOver() {
+		return Hint{}, ErrGameOver
+	}
+
+	h, err := g.hint()
+	if err != nil {
+		return Hint{}, err
+	}
+
+	g.stats.HintsUsed++
+
+	return h, nil
+}
+
+func (g *Game) hint() (Hint, error) {
+	state := g.board.State()
+	moves := solver.Solve(state, g.cfg.NumBlackHoles)
+
+	for _, m := range moves {
+		if !m.BlackHole {
+			return Hint{Position: m.Position, Certain: true, Reason: m.Reason}, nil
+		}
+	}
+
+	if len(moves) > 0 {
+		return Hint{Position: moves[0].Position, BlackHole: true, Certain: true, Probability: 1,
+			Reason: moves[0].Reason}, nil
+	}
+
+	return leastRiskyCell(state, g.cfg.NumBlackHoles)
+}
+
+// leastRiskyCell returns the unknown cell with the lowest probability of a black hole.
+// A cell that has no black hole in any arrangement matching the clues is reported as a certain safe cell.
+func leastRiskyCell(state [][]board.CellValue, totalBlackHoles int) (Hint, error) {
+	result, err := probability.Calculate(state, totalBlackHoles, hintTimeBudget)
+	if err != nil {
+		return Hint{}, fmt.Errorf("failed to calculate probabilities: %w", err)
+	}
+
+	var (
+		best  Hint
+		found bool
+	)
+
+	for i, row := range state {
+		for j, v := range row {
+			if !v.IsHidden() {
+				continue
+			}
+
+			p := result.Probabilities[i][j]
+
+			if !found || p < best.Probability {
+				best = Hint{Position: board.Position{Row: i, Col: j}, Probability: p}
+				found = true
+			}
+		}
+	}
+
+	if result.Exact && best.Probability == 0 {
+		best.Certain = true
+		best.Reason = solver.NewReason(
+			"no arrangement of the remaining black holes that matches the clues puts a black hole there")
+
+		return best, nil
+	}
+
+	best.Reason = solver.NewReason("no certain move, the cell at %s has a %.0f%% chance of a black hole",
+		best.Position, best.Probability*100)
+
+	return best, nil
+}
@@ -0,0 +1,68 @@
+package game_test
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Hint(t *testing.T) {
+	gameCfg := game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	}
+
+	t.Run("Safe cell proved by the revealed clues", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(2, 0)
+		require.NoError(t, err)
+
+		hint, err := g.Hint()
+		require.NoError(t, err)
+
+		assert.True(t, hint.Certain)
+		assert.False(t, hint.BlackHole)
+		assert.EqualValues(t, board.Position{Row: 0, Col: 2}, hint.Position)
+		assert.EqualValues(t, "the 1 at (0,1) is already satisfied by the black hole at (1,2)",
+			hint.Reason.Explain(func(p board.Position) string { return fmt.Sprintf("%d,%d", p.Row, p.Col) }))
+		assert.EqualValues(t, 1, g.Stats().HintsUsed)
+	})
+
+	t.Run("No certain move", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		hint, err := g.Hint()
+		require.NoError(t, err)
+
+		assert.False(t, hint.Certain)
+		assert.EqualValues(t, board.Position{Row: 0, Col: 0}, hint.Position)
+		assert.InDelta(t, 2.0/9.0, hint.Probability, 1e-9)
+	})
+
+	t.Run("Game is over", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(1, 2)
+		require.NoError(t, err)
+
+		_, err = g.Hint()
+		assert.ErrorIs(t, err, game.ErrGameOver)
+		assert.EqualValues(t, 0, g.Stats().HintsUsed)
+	})
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/board"
+	"time"
+)
+
+var ErrUnknownMoveKind = errors.New("unknown move kind")
+
+// MoveKind represents a kind of player's move.
+type MoveKind int
+
+const (
+	MoveOpen MoveKind = iota
+	MoveToggleFlag
+	MoveChord
+	// MoveResign gives the game up, its position is meaningless.
+	MoveResign
+)
+
+func (k MoveKind) String() string {
+	switch k {
+	case MoveOpen:
+		return "open"
+	case MoveToggleFlag:
+		return "flag"
+	case MoveChord:
+		return "chord"
+	case MoveResign:
+		return "resign"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMoveKind returns the kind of move with the specified name.
+func ParseMoveKind(s string) (MoveKind, error) {
+	for _, k := range []MoveKind{MoveOpen, MoveToggleFlag, MoveChord, MoveResign} {
+		if k.String() == s {
+			return k, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownMoveKind, s)
+}
+
+// Move represents a single move of a player.
+// Elapsed is the game time when the move was made.
+type Move struct {
+	Kind     MoveKind
+	Position board.Position
+	Elapsed  time.Duration
+}
+
+// History returns all the moves made in the game in the order they were made.
+func (g *Game) History() []Move {
+	history := make([]Move, len(g.history))
+	copy(history, g.history)
+
+	return history
+}
+
+// record adds the move to the history. A new move makes the undone moves impossible to redo.
+func (g *Game) record(kind MoveKind, pos board.Position) {
+	g.history = append(g.history, Move{Kind: kind, Position: pos, Elapsed: g.Elapsed()})
+	g.undone = nil
+}
@@ -0,0 +1,19 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownLocator = errors.New("unknown black hole locator")
+
+// NewBlackHoleLocator returns the seeded locator with the specified name.
+// Locators created with the same name and seed place black holes at the same positions.
+func NewBlackHoleLocator(name string, seed int64) (BlackHoleLocator, error) {
+	switch name {
+	case UniformLocatorName:
+		return NewSeededUniformBlackHoleLocator(seed), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLocator, name)
+	}
+}
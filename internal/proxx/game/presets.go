@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrUnknownPreset    = errors.New("unknown preset")
+	ErrDuplicatedPreset = errors.New("duplicated preset")
+	ErrInvalidPreset    = errors.New("invalid preset")
+)
+
+// Preset represents a named board configuration.
+// The number of black holes is given either by NumBlackHoles or by BlackHoleDensity.
+type Preset struct {
+	Name             string
+	NumRows          int
+	NumCols          int
+	NumBlackHoles    int
+	BlackHoleDensity float64
+}
+
+// presets are the classic difficulty levels.
+var presets = []Preset{
+	{Name: "beginner", NumRows: 9, NumCols: 9, NumBlackHoles: 10},
+	{Name: "intermediate", NumRows: 16, NumCols: 16, NumBlackHoles: 40},
+	{Name: "expert", NumRows: 16, NumCols: 30, NumBlackHoles: 99},
+}
+
+// Presets returns the built-in presets followed by the specified user-defined ones.
+func Presets(custom ...Preset) []Preset {
+	result := make([]Preset, 0, len(presets)+len(custom))
+	result = append(result, presets...)
+
+	return append(result, custom...)
+}
+
+// PresetByName returns the preset with the specified name.
+// User-defined presets are looked up after the built-in ones.
+func PresetByName(name string, custom ...Preset) (Preset, error) {
+	for _, p := range Presets(custom...) {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return Preset{}, fmt.Errorf("%w: %q", ErrUnknownPreset, name)
+}
+
+// Config returns the game configuration of the preset that uses the specified locator.
+func (p Preset) Config(locator BlackHoleLocator) Config {
+	return Config{
+		NumRows:          p.NumRows,
+		NumCols:          p.NumCols,
+		NumBlackHoles:    p.NumBlackHoles,
+		BlackHoleDensity: p.BlackHoleDensity,
+		BlackHoleLocator: locator,
+	}
+}
+
+// savedPreset represents a user-defined preset in a JSON document.
+type savedPreset struct {
+	Name    string  `json:"name"`
+	Rows    int     `json:"rows"`
+	Cols    int     `json:"cols"`
+	Holes   int     `json:"holes,omitempty"`
+	Density float64 `json:"density,omitempty"`
+}
+
+// ReadPresets reads user-defined presets from r. The document is a JSON array of objects like
+// {"name": "huge", "rows": 30, "cols": 50, "density": 18}, where "holes" may be used instead of "density".
+// Every preset must describe a valid game and its name must differ from the names of the other presets.
+func ReadPresets(r io.Reader) ([]Preset, error) {
+	var docs []savedPreset
+
+	if err := json.NewDecoder(r).Decode(&docs); err != nil {
+		return nil, fmt.Errorf("failed to decode presets: %w", err)
+	}
+
+	custom := make([]Preset, 0, len(docs))
+
+	for _, doc := range docs {
+		p := Preset{
+			Name:             doc.Name,
+			NumRows:          doc.Rows,
+			NumCols:          doc.Cols,
+			NumBlackHoles:    doc.Holes,
+			BlackHoleDensity: doc.Density,
+		}
+
+		if p.Name == "" {
+			return nil, fmt.Errorf("%w: name should be provided", ErrInvalidPreset)
+		}
+
+		if _, err := PresetByName(p.Name, custom...); err == nil {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicatedPreset, p.Name)
+		}
+
+		if err := p.Config(NewFixedBlackHoleLocator(nil)).Validate(); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidPreset, p.Name, err)
+		}
+
+		custom = append(custom, p)
+	}
+
+	return custom, nil
+}
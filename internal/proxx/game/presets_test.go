@@ -0,0 +1,81 @@
+package game_test
+
+import (
+	"proxx/internal/proxx/game"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetByName(t *testing.T) {
+	bhLocator := game.NewUniformBlackHoleLocator()
+
+	for _, p := range game.Presets() {
+		p := p
+		t.Run(p.Name, func(t *testing.T) {
+			t.Parallel()
+
+			preset, err := game.PresetByName(p.Name)
+			require.NoError(t, err)
+			assert.EqualValues(t, p, preset)
+			assert.NoError(t, preset.Config(bhLocator).Validate())
+		})
+	}
+
+	t.Run("Unknown preset", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := game.PresetByName("impossible")
+		assert.ErrorIs(t, err, game.ErrUnknownPreset)
+	})
+}
+
+func TestReadPresets(t *testing.T) {
+	t.Run("User-defined presets", func(t *testing.T) {
+		t.Parallel()
+
+		doc := `[{"name": "huge", "rows": 30, "cols": 50, "density": 18}, {"name": "tiny", "rows": 3, "cols": 3, "holes": 1}]`
+
+		custom, err := game.ReadPresets(strings.NewReader(doc))
+		require.NoError(t, err)
+
+		expected := []game.Preset{
+			{Name: "huge", NumRows: 30, NumCols: 50, BlackHoleDensity: 18},
+			{Name: "tiny", NumRows: 3, NumCols: 3, NumBlackHoles: 1},
+		}
+		assert.EqualValues(t, expected, custom)
+
+		preset, err := game.PresetByName("huge", custom...)
+		require.NoError(t, err)
+		assert.Equal(t, 270, preset.Config(nil).BlackHoleCount())
+		assert.Len(t, game.Presets(custom...), len(game.Presets())+2)
+	})
+
+	testCases := []struct {
+		name string
+		doc  string
+		err  error
+	}{
+		{name: "Same name as a built-in preset", doc: `[{"name": "expert", "rows": 3, "cols": 3, "holes": 1}]`,
+			err: game.ErrDuplicatedPreset},
+		{name: "Same name twice", doc: `[{"name": "a", "rows": 3, "cols": 3, "holes": 1}, {"name": "a", "rows": 4, "cols": 4, "holes": 1}]`,
+			err: game.ErrDuplicatedPreset},
+		{name: "Missing name", doc: `[{"rows": 3, "cols": 3, "holes": 1}]`, err: game.ErrInvalidPreset},
+		{name: "Density is too high", doc: `[{"name": "a", "rows": 3, "cols": 3, "density": 95}]`,
+			err: game.ErrTooManyBlackHoles},
+		{name: "Count and density", doc: `[{"name": "a", "rows": 3, "cols": 3, "holes": 1, "density": 10}]`,
+			err: game.ErrBlackHoleCountAndDensity},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := game.ReadPresets(strings.NewReader(tc.doc))
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
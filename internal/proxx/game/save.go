@@ -0,0 +1,283 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/board"
+	"time"
+)
+
+// saveFormatVersion is the version of the saved game document.
+// It must be increased on every incompatible change of the document.
+const saveFormatVersion = 1
+
+var (
+	ErrUnsupportedSaveVersion = errors.New("unsupported version of the saved game")
+	ErrInconsistentSave       = errors.New("saved game is inconsistent")
+)
+
+// savedGame represents a game saved as a JSON document.
+// The game is restored by replaying the history on the board with the saved black holes,
+// opened and flagged cells are stored to check that the document is consistent.
+type savedGame struct {
+	Version        int             `json:"version"`
+	Rows           int             `json:"rows"`
+	Cols           int             `json:"cols"`
+	FirstClickSafe bool            `json:"first_click_safe,omitempty"`
+	BlackHoles     []savedPosition `json:"black_holes"`
+	Opened         []savedCell     `json:"opened"`
+	Flagged        []savedPosition `json:"flagged"`
+	ElapsedMs      int64           `json:"elapsed_ms"`
+	Locator        string          `json:"locator,omitempty"`
+	Seed           *int64          `json:"seed,omitempty"`
+	HintsUsed      int             `json:"hints_used"`
+	Undos          int             `json:"undos"`
+	History        []savedMove     `json:"history"`
+}
+
+// savedPosition represents a position as a pair [row, col].
+type savedPosition [2]int
+
+type savedCell struct {
+	Position savedPosition   `json:"pos"`
+	Value    board.CellValue `json:"value"`
+}
+
+type savedMove struct {
+	Kind      string        `json:"kind"`
+	Position  savedPosition `json:"pos"`
+	ElapsedMs int64         `json:"elapsed_ms"`
+}
+
+// Save writes the game to w as a versioned JSON document.
+func (g *Game) Save(w io.Writer) error {
+	doc := savedGame{
+		Version:        saveFormatVersion,
+		Rows:           g.cfg.NumRows,
+		Cols:           g.cfg.NumCols,
+		FirstClickSafe: g.cfg.FirstClickSafe,
+		BlackHoles:     toSavedPositions(g.board.BlackHolePositions()),
+		Opened:         []savedCell{},
+		Flagged:        []savedPosition{},
+		ElapsedMs:      g.Elapsed().Milliseconds(),
+		HintsUsed:      g.stats.HintsUsed,
+		Undos:          g.stats.Undos,
+		History:        make([]savedMove, 0, len(g.history)),
+		Locator:        g.locator.name,
+		Seed:           g.locator.seed,
+	}
+
+	for i := 0; i < g.cfg.NumRows; i++ {
+		for j := 0; j < g.cfg.NumCols; j++ {
+			cell := g.board.CellAt(i, j)
+			pos := savedPosition{i, j}
+
+			if cell.IsOpen() {
+				doc.Opened = append(doc.Opened, savedCell{Position: pos, Value: cell.Value()})
+			} else if cell.IsFlagged() {
+				doc.Flagged = append(doc.Flagged, pos)
+			}
+		}
+	}
+
+	for _, m := range g.history {
+		doc.History = append(doc.History, savedMove{
+			Kind:      m.Kind.String(),
+			Position:  toSavedPosition(m.Position),
+			ElapsedMs: m.Elapsed.Milliseconds(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode the game: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a game saved by Save from r.
+// The history is replayed on the board with the saved black holes, the result must match
+// the saved opened cells (including their clues) and flags. The loaded game's clock is paused.
+func Load(r io.Reader) (*Game, error) {
+	var doc savedGame
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode the game: %w", err)
+	}
+
+	if doc.Version != saveFormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSaveVersion, doc.Version)
+	}
+
+	blackHoles, err := fromSavedPositions(doc.BlackHoles, doc.Rows, doc.Cols)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := NewGame(Config{
+		NumRows:          doc.Rows,
+		NumCols:          doc.Cols,
+		NumBlackHoles:    len(blackHoles),
+		BlackHoleLocator: NewFixedBlackHoleLocator(blackHoles),
+		FirstClickSafe:   doc.FirstClickSafe,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInconsistentSave, err)
+	}
+
+	history := make([]Move, 0, len(doc.History))
+
+	for i, sm := range doc.History {
+		m, err := fromSavedMove(sm)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := g.Apply(m); err != nil {
+			return nil, fmt.Errorf("%w: move #%d (%s at %v): %w", ErrInconsistentSave, i+1, m.Kind, m.Position, err)
+		}
+
+		history = append(history, m)
+	}
+
+	if err := g.checkSavedCells(doc); err != nil {
+		return nil, err
+	}
+
+	g.history = history
+	g.locator = locatorInfo{name: doc.Locator, seed: doc.Seed}
+	g.stats = Stats{HintsUsed: doc.HintsUsed, Undos: doc.Undos}
+	g.clock = clock{elapsed: time.Duration(doc.ElapsedMs) * time.Millisecond, started: len(history) > 0}
+
+	return g, nil
+}
+
+// locatorInfo describes the locator that placed black holes on the board.
+// A restored game keeps the description of the original locator.
+type locatorInfo struct {
+	name string
+	seed *int64
+}
+
+func describeLocator(l BlackHoleLocator) locatorInfo {
+	var info locatorInfo
+
+	if named, ok := l.(interface{ Name() string }); ok {
+		info.name = named.Name()
+	}
+
+	if seeded, ok := l.(interface{ Seed() int64 }); ok {
+		seed := seeded.Seed()
+		info.seed = &seed
+	}
+
+	return info
+}
+
+// Apply makes the move on the game. Elapsed time of the move is ignored.
+func (g *Game) Apply(m Move) error {
+	switch m.Kind {
+	case MoveOpen:
+		_, err := g.OpenCell(m.Position.Row, m.Position.Col)
+		return err
+	case MoveToggleFlag:
+		return g.ToggleFlag(m.Position.Row, m.Position.Col)
+	case MoveChord:
+		_, err := g.Chord(m.Position.Row, m.Position.Col)
+		return err
+	case MoveResign:
+		return g.Resign()
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownMoveKind, m.Kind)
+	}
+}
+
+// checkSavedCells compares the saved opened and flagged cells with the ones restored from the history.
+func (g *Game) checkSavedCells(doc savedGame) error {
+	opened := make(map[savedPosition]board.CellValue, len(doc.Opened))
+	for _, c := range doc.Opened {
+		opened[c.Position] = c.Value
+	}
+
+	flagged := make(map[savedPosition]bool, len(doc.Flagged))
+	for _, p := range doc.Flagged {
+		flagged[p] = true
+	}
+
+	for i := 0; i < g.cfg.NumRows; i++ {
+		for j := 0; j < g.cfg.NumCols; j++ {
+			cell := g.board.CellAt(i, j)
+			pos := savedPosition{i, j}
+
+			value, isOpen := opened[pos]
+
+			if cell.IsOpen() != isOpen {
+				return fmt.Errorf("%w: cell %v is not reachable by the saved moves", ErrInconsistentSave, pos)
+			}
+
+			if isOpen && value != cell.Value() {
+				return fmt.Errorf("%w: cell %v has value %q, but black holes give %q",
+					ErrInconsistentSave, pos, value, cell.Value())
+			}
+
+			if cell.IsFlagged() != flagged[pos] {
+				return fmt.Errorf("%w: flag at %v doesn't match the saved moves", ErrInconsistentSave, pos)
+			}
+		}
+	}
+
+	return nil
+}
+
+func toSavedPosition(p board.Position) savedPosition {
+	return savedPosition{p.Row, p.Col}
+}
+
+func toSavedPositions(positions []board.Position) []savedPosition {
+	saved := make([]savedPosition, 0, len(positions))
+
+	for _, p := range positions {
+		saved = append(saved, toSavedPosition(p))
+	}
+
+	return saved
+}
+
+// fromSavedPositions converts the positions of black holes and checks they are unique and within the board.
+func fromSavedPositions(saved []savedPosition, rows int, cols int) ([]board.Position, error) {
+	positions := make([]board.Position, 0, len(saved))
+	seen := make(map[savedPosition]bool, len(saved))
+
+	for _, sp := range saved {
+		if sp[0] < 0 || sp[1] < 0 || sp[0] >= rows || sp[1] >= cols {
+			return nil, fmt.Errorf("%w: black hole %v: %w", ErrInconsistentSave, sp, ErrCellPositionIsOutsideBoard)
+		}
+
+		if seen[sp] {
+			return nil, fmt.Errorf("%w: duplicated black hole %v", ErrInconsistentSave, sp)
+		}
+
+		seen[sp] = true
+		positions = append(positions, board.Position{Row: sp[0], Col: sp[1]})
+	}
+
+	return positions, nil
+}
+
+func fromSavedMove(sm savedMove) (Move, error) {
+	kind, err := ParseMoveKind(sm.Kind)
+	if err != nil {
+		return Move{}, fmt.Errorf("%w: %w", ErrInconsistentSave, err)
+	}
+
+	return Move{
+		Kind:     kind,
+		Position: board.Position{Row: sm.Position[0], Col: sm.Position[1]},
+		Elapsed:  time.Duration(sm.ElapsedMs) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package game_test
+
+import (
+	"bytes"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_SaveAndLoad(t *testing.T) {
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		FirstClickSafe:   true,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, g.ToggleFlag(2, 2))
+	_, err = g.OpenCell(1, 1)
+	require.NoError(t, err)
+	_, err = g.Hint()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+
+	loaded, err := game.Load(&buf)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, g.BoardState(), loaded.BoardState())
+	require.Len(t, loaded.History(), len(g.History()))
+	for i, m := range g.History() {
+		assert.EqualValues(t, m.Kind, loaded.History()[i].Kind)
+		assert.EqualValues(t, m.Position, loaded.History()[i].Position)
+		assert.EqualValues(t, m.Elapsed.Truncate(time.Millisecond), loaded.History()[i].Elapsed)
+	}
+	assert.EqualValues(t, g.Stats(), loaded.Stats())
+	assert.EqualValues(t, g.Status(), loaded.Status())
+	assert.Equal(t, g.Config().FirstClickSafe, loaded.Config().FirstClickSafe)
+	assert.True(t, loaded.IsPaused())
+	assert.LessOrEqual(t, loaded.Elapsed(), g.Elapsed())
+
+	_, err = loaded.OpenCell(0, 2)
+	require.NoError(t, err)
+	assert.False(t, loaded.IsOver())
+}
+
+func TestGame_SaveAndLoad_Resigned(t *testing.T) {
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	})
+	require.NoError(t, err)
+
+	_, err = g.OpenCell(1, 1)
+	require.NoError(t, err)
+	require.NoError(t, g.Resign())
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+
+	loaded, err := game.Load(&buf)
+	require.NoError(t, err)
+
+	assert.True(t, loaded.IsResigned())
+	assert.Equal(t, game.StatusLost, loaded.Status())
+	assert.EqualValues(t, g.BoardState(), loaded.BoardState())
+	require.Len(t, loaded.History(), 2)
+	assert.Equal(t, game.MoveResign, loaded.History()[1].Kind)
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name string
+		doc  string
+		err  error
+	}{
+		{
+			name: "Valid document",
+			doc: `{"version": 1, "rows": 2, "cols": 2, "black_holes": [[0, 0]],
+				"opened": [{"pos": [1, 1], "value": "1"}], "flagged": [[0, 0]],
+				"history": [{"kind": "open", "pos": [1, 1]}, {"kind": "flag", "pos": [0, 0]}]}`,
+		},
+		{
+			name: "Unsupported version",
+			doc:  `{"version": 2, "rows": 2, "cols": 2, "black_holes": [[0, 0]]}`,
+			err:  game.ErrUnsupportedSaveVersion,
+		},
+		{
+			name: "Black hole outside the board",
+			doc:  `{"version": 1, "rows": 2, "cols": 2, "black_holes": [[2, 0]]}`,
+			err:  game.ErrInconsistentSave,
+		},
+		{
+			name: "Clue doesn't match black holes",
+			doc: `{"version": 1, "rows": 2, "cols": 2, "black_holes": [[0, 0]],
+				"opened": [{"pos": [1, 1], "value": "2"}], "history": [{"kind": "open", "pos": [1, 1]}]}`,
+			err: game.ErrInconsistentSave,
+		},
+		{
+			name: "Opened cell isn't reachable",
+			doc: `{"version": 1, "rows": 2, "cols": 2, "black_holes": [[0, 0]],
+				"opened": [{"pos": [1, 1], "value": "1"}, {"pos": [0, 1], "value": "1"}],
+				"history": [{"kind": "open", "pos": [1, 1]}]}`,
+			err: game.ErrInconsistentSave,
+		},
+		{
+			name: "Invalid move",
+			doc: `{"version": 1, "rows": 2, "cols": 2, "black_holes": [[0, 0]],
+				"history": [{"kind": "open", "pos": [1, 1]}, {"kind": "open", "pos": [1, 1]}]}`,
+			err: game.ErrInconsistentSave,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := game.Load(strings.NewReader(tc.doc))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package game
+
+// Stats represents statistics of a game.
+type Stats struct {
+	HintsUsed int
+	Undos     int
+}
+
+// Stats returns the current statistics of the game.
+func (g *Game) Stats() Stats {
+	return g.stats
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/board"
+)
+
+var (
+	ErrNothingToUndo = errors.New("there is no move to undo")
+	ErrNothingToRedo = errors.New("there is no move to redo")
+)
+
+// Undo takes back the last move, even the one that lost the game. A resigned game can't be undone.
+// The game is restored by replaying the remaining moves on the same black holes, the clock keeps running.
+// Undone moves can be redone until a new move is made. Every call is counted in the game's statistics.
+func (g *Game) Undo() error {
+	if g.resigned {
+		return ErrGameOver
+	}
+
+	if len(g.history) == 0 {
+		return ErrNothingToUndo
+	}
+
+	last := g.history[len(g.history)-1]
+
+	if err := g.restore(g.history[:len(g.history)-1]); err != nil {
+		return err
+	}
+
+	g.undone = append(g.undone, last)
+	g.stats.Undos++
+
+	g.emit(MoveUndone{Move: last})
+	g.Resume()
+
+	return nil
+}
+
+// Redo makes the last undone move again.
+func (g *Game) Redo() error {
+	if len(g.undone) == 0 {
+		return ErrNothingToRedo
+	}
+
+	m := g.undone[len(g.undone)-1]
+	undone := g.undone[:len(g.undone)-1]
+
+	if err := g.Apply(m); err != nil {
+		return err
+	}
+
+	g.undone = undone
+
+	return nil
+}
+
+// restore replaces the board with the one that results from the moves made on the same black holes.
+// The moves keep their time in the history.
+func (g *Game) restore(moves []Move) error {
+	b, err := board.NewBoard(board.Config{NumRows: g.cfg.NumRows, NumCols: g.cfg.NumCols})
+	if err != nil {
+		return fmt.Errorf("failed to restore the game: %w", err)
+	}
+
+	b.Init(g.board.BlackHolePositions())
+
+	restored := &Game{board: b, cfg: g.cfg}
+
+	for _, m := range moves {
+		if err := restored.Apply(m); err != nil {
+			return fmt.Errorf("failed to restore the game: %w", err)
+		}
+	}
+
+	g.board = restored.board
+	g.isLost = restored.isLost
+	g.isWon = restored.isWon
+	g.lostAt = restored.lostAt
+	g.history = append([]Move(nil), moves...)
+
+	return nil
+}
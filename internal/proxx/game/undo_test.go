@@ -0,0 +1,102 @@
+package game_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/testhelpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_UndoRedo(t *testing.T) {
+	gameCfg := game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: newPredefinedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}),
+	}
+
+	t.Run("Nothing to undo or redo", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, g.Undo(), game.ErrNothingToUndo)
+		assert.ErrorIs(t, g.Redo(), game.ErrNothingToRedo)
+	})
+
+	t.Run("Undo and redo a flag", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+		require.NoError(t, g.ToggleFlag(1, 2))
+
+		var events []game.Event
+		g.Subscribe(func(e game.Event) { events = append(events, e) })
+
+		require.NoError(t, g.Undo())
+		assert.EqualValues(t, board.CellValueUnknown, g.BoardState()[1][2])
+		assert.EqualValues(t, "1", g.BoardState()[0][2])
+		assert.Len(t, g.History(), 1)
+		assert.Equal(t, 1, g.Stats().Undos)
+		require.NotEmpty(t, events)
+		assert.Equal(t, game.MoveUndone{Move: game.Move{Kind: game.MoveToggleFlag,
+			Position: board.Position{Row: 1, Col: 2}, Elapsed: events[0].(game.MoveUndone).Move.Elapsed}}, events[0])
+
+		require.NoError(t, g.Redo())
+		assert.EqualValues(t, board.CellValueFlag, g.BoardState()[1][2])
+		assert.Len(t, g.History(), 2)
+	})
+
+	t.Run("Undo the move that lost the game", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(2, 0)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(2, 2)
+		require.NoError(t, err)
+		require.True(t, g.IsOver())
+
+		require.NoError(t, g.Undo())
+		assert.False(t, g.IsOver())
+		assert.Equal(t, game.StatusInProgress, g.Status())
+
+		expectedState := [][]board.CellValue{
+			{"0", "1", "?"},
+			{"0", "2", "?"},
+			{"0", "2", "?"},
+		}
+		testhelpers.EqualBoardStates(t, expectedState, g.BoardState())
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+		assert.True(t, g.IsWon())
+	})
+
+	t.Run("New move can't be followed by redo", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+		require.NoError(t, g.Undo())
+
+		_, err = g.OpenCell(0, 1)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, g.Redo(), game.ErrNothingToRedo)
+	})
+}
@@ -6,15 +6,35 @@ import (
 	"time"
 )
 
+// UniformLocatorName is the name of UniformBlackHoleLocator.
+const UniformLocatorName = "uniform"
+
 // UniformBlackHoleLocator uniformly distributes black holes across a game board.
 // Each cell has a 50% chance to be chosen as a place for a black hole.
 type UniformBlackHoleLocator struct {
-	rg *rand.Rand
+	rg   *rand.Rand
+	seed int64
 }
 
-// NewUniformBlackHoleLocator returns new UniformBlackHoleLocator object.
+// NewUniformBlackHoleLocator returns new UniformBlackHoleLocator object seeded with the current time.
 func NewUniformBlackHoleLocator() *UniformBlackHoleLocator {
-	return &UniformBlackHoleLocator{rg: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	return NewSeededUniformBlackHoleLocator(time.Now().UnixNano())
+}
+
+// NewSeededUniformBlackHoleLocator returns new UniformBlackHoleLocator object with the specified seed.
+// Locators with the same seed place black holes at the same positions.
+func NewSeededUniformBlackHoleLocator(seed int64) *UniformBlackHoleLocator {
+	return &UniformBlackHoleLocator{rg: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// Name returns the name of the locator.
+func (l UniformBlackHoleLocator) Name() string {
+	return UniformLocatorName
+}
+
+// Seed returns the seed of the locator's random generator.
+func (l UniformBlackHoleLocator) Seed() int64 {
+	return l.seed
 }
 
 // LocateBlackHolesOnBoard returns positions of black holes on a game board
@@ -49,3 +69,8 @@ func (l UniformBlackHoleLocator) LocateBlackHolesOnBoard(rows int, cols int, bhN
 
 	return positions
 }
+
+// intn returns a random number in [0, n) from the locator's generator.
+func (l UniformBlackHoleLocator) intn(n int) int {
+	return l.rg.Intn(n)
+}
@@ -0,0 +1,507 @@
+// Package probability calculates chances of black holes for unknown cells of a game board.
+package probability
+
+import (
+	"errors"
+	"math"
+	"proxx/internal/proxx/board"
+	"time"
+)
+
+var (
+	ErrInconsistentState = errors.New("no arrangement of black holes matches the board state")
+	ErrTooManyBlackHoles = errors.New("more black holes revealed than the total number of black holes")
+)
+
+// Result represents chances of black holes for all the cells of a board.
+// Probabilities has the same dimensions as the board state.
+// Opened cells have zero probability, revealed black holes - one.
+// Exact is false when the time budget ran out and the probabilities were approximated.
+type Result struct {
+	Probabilities [][]float64
+	Exact         bool
+}
+
+// Calculate returns the probability of a black hole for every unknown cell of the board state.
+// The state is the one returned by board.Board.State(): only opened cells are known.
+// totalBlackHoles is the number of black holes on the whole board.
+//
+// Unknown cells next to clues (the frontier) are split into independent parts.
+// All arrangements of black holes are enumerated for each part and the arrangements are weighted
+// by the number of ways to place the rest of black holes into the cells that aren't next to clues.
+// If the budget is positive and the enumeration takes longer, approximate probabilities are returned.
+func Calculate(state [][]board.CellValue, totalBlackHoles int, budget time.Duration) (Result, error) {
+	var l limit
+	if budget > 0 {
+		l.deadline = time.Now().Add(budget)
+	}
+
+	return calculate(state, totalBlackHoles, &l)
+}
+
+// CalculateSteps works like Calculate, but the enumeration is limited by the number of steps instead of time.
+// Unlike a time budget, it gives the same result on every machine and under any load.
+// If maxSteps isn't positive, the enumeration isn't limited.
+func CalculateSteps(state [][]board.CellValue, totalBlackHoles int, maxSteps int) (Result, error) {
+	return calculate(state, totalBlackHoles, &limit{maxSteps: maxSteps})
+}
+
+func calculate(state [][]board.CellValue, totalBlackHoles int, l *limit) (Result, error) {
+	p, err := newProblem(state, totalBlackHoles)
+	if err != nil {
+		return Result{}, err
+	}
+
+	parts := p.parts()
+
+	for _, part := range parts {
+		if !part.enumerate(p, l) {
+			return Result{Probabilities: p.approximate(), Exact: false}, nil
+		}
+	}
+
+	probabilities, err := p.combine(parts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Probabilities: probabilities, Exact: true}, nil
+}
+
+// limit stops the enumeration at the deadline or after the maximum number of steps, zero values don't limit it.
+// The steps are counted across all the parts of the frontier.
+type limit struct {
+	deadline time.Time
+	maxSteps int
+	steps    int
+}
+
+// step counts a step of the enumeration and returns false if the limit is reached.
+func (l *limit) step() bool {
+	l.steps++
+
+	if l.maxSteps > 0 && l.steps > l.maxSteps {
+		return false
+	}
+
+	return l.deadline.IsZero() || l.steps%1024 != 0 || !time.Now().After(l.deadline)
+}
+
+// constraint represents a clue: exactly need black holes among cells.
+type constraint struct {
+	need  int
+	cells []int
+}
+
+// problem represents unknown cells of a board and clues restricting them.
+type problem struct {
+	state         [][]board.CellValue
+	frontier      []board.Position
+	index         map[board.Position]int
+	constraints   []constraint
+	cellClues     [][]int
+	unconstrained []board.Position
+	remaining     int
+}
+
+func newProblem(state [][]board.CellValue, totalBlackHoles int) (*problem, error) {
+	p := &problem{state: state, index: make(map[board.Position]int), remaining: totalBlackHoles}
+
+	isFrontier := make(map[board.Position]bool)
+
+	for i, row := range state {
+		for j, v := range row {
+			if v == board.CellValueBlackHole {
+				p.remaining--
+				continue
+			}
+
+			value, ok := v.Clue()
+			if !ok {
+				continue
+			}
+
+			c := constraint{need: value}
+
+			for _, n := range board.SurroundingPositions(len(state), len(row), i, j) {
+				switch nv := state[n.Row][n.Col]; {
+				case nv == board.CellValueBlackHole:
+					c.need--
+				case nv.IsHidden():
+					if _, seen := p.index[n]; !seen {
+						p.index[n] = len(p.frontier)
+						p.frontier = append(p.frontier, n)
+						p.cellClues = append(p.cellClues, nil)
+					}
+
+					isFrontier[n] = true
+					c.cells = append(c.cells, p.index[n])
+				}
+			}
+
+			if c.need < 0 || c.need > len(c.cells) {
+				return nil, ErrInconsistentState
+			}
+
+			if len(c.cells) == 0 {
+				continue
+			}
+
+			for _, cell := range c.cells {
+				p.cellClues[cell] = append(p.cellClues[cell], len(p.constraints))
+			}
+
+			p.constraints = append(p.constraints, c)
+		}
+	}
+
+	if p.remaining < 0 {
+		return nil, ErrTooManyBlackHoles
+	}
+
+	for i, row := range state {
+		for j, v := range row {
+			pos := board.Position{Row: i, Col: j}
+
+			if v.IsHidden() && !isFrontier[pos] {
+				p.unconstrained = append(p.unconstrained, pos)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// parts splits the frontier into independent parts: cells of different parts never share a clue.
+func (p *problem) parts() []*part {
+	parent := make([]int, len(p.frontier))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for _, c := range p.constraints {
+		for _, cell := range c.cells[1:] {
+			parent[find(cell)] = find(c.cells[0])
+		}
+	}
+
+	byRoot := make(map[int]*part)
+
+	var parts []*part
+
+	for _, cell := range p.bfsOrder() {
+		root := find(cell)
+
+		pt, ok := byRoot[root]
+		if !ok {
+			pt = &part{}
+			byRoot[root] = pt
+			parts = append(parts, pt)
+		}
+
+		pt.cells = append(pt.cells, cell)
+	}
+
+	return parts
+}
+
+// bfsOrder orders frontier cells so that neighboring cells go one after another.
+// It lets the enumeration complete clues early and cut off inconsistent arrangements sooner.
+func (p *problem) bfsOrder() []int {
+	visited := make([]bool, len(p.frontier))
+	order := make([]int, 0, len(p.frontier))
+
+	for start := range p.frontier {
+		if visited[start] {
+			continue
+		}
+
+		visited[start] = true
+		queue := []int{start}
+
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			order = append(order, cell)
+
+			for _, c := range p.cellClues[cell] {
+				for _, next := range p.constraints[c].cells {
+					if !visited[next] {
+						visited[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+
+	return order
+}
+
+// part represents an independent part of the frontier.
+// counts[k] is the number of arrangements with k black holes,
+// cellCounts[k][i] is the number of such arrangements that have a black hole in the i-th cell of the part.
+type part struct {
+	cells      []int
+	counts     []float64
+	cellCounts [][]float64
+}
+
+// enumerate counts all the arrangements of black holes that satisfy the clues.
+// Returns false if the limit has been reached.
+func (pt *part) enumerate(p *problem, l *limit) bool {
+	pt.counts = make([]float64, len(pt.cells)+1)
+	pt.cellCounts = make([][]float64, len(pt.cells)+1)
+
+	for k := range pt.cellCounts {
+		pt.cellCounts[k] = make([]float64, len(pt.cells))
+	}
+
+	holes := make([]int, len(p.constraints))
+	left := make([]int, len(p.constraints))
+
+	for i, c := range p.constraints {
+		left[i] = len(c.cells)
+	}
+
+	assignment := make([]bool, len(pt.cells))
+
+	var expired bool
+
+	var assign func(i int, k int)
+	assign = func(i int, k int) {
+		if expired {
+			return
+		}
+
+		if !l.step() {
+			expired = true
+			return
+		}
+
+		if k > p.remaining {
+			return
+		}
+
+		if i == len(pt.cells) {
+			pt.counts[k]++
+
+			for j, isHole := range assignment {
+				if isHole {
+					pt.cellCounts[k][j]++
+				}
+			}
+
+			return
+		}
+
+		cell := pt.cells[i]
+
+		for _, isHole := range []bool{false, true} {
+			consistent := true
+
+			for _, c := range p.cellClues[cell] {
+				left[c]--
+				if isHole {
+					holes[c]++
+				}
+
+				need := p.constraints[c].need
+				if holes[c] > need || holes[c]+left[c] < need {
+					consistent = false
+				}
+			}
+
+			if consistent {
+				assignment[i] = isHole
+
+				next := k
+				if isHole {
+					next++
+				}
+
+				assign(i+1, next)
+			}
+
+			for _, c := range p.cellClues[cell] {
+				left[c]++
+				if isHole {
+					holes[c]--
+				}
+			}
+		}
+
+		assignment[i] = false
+	}
+
+	assign(0, 0)
+
+	return !expired
+}
+
+// combine weighs arrangements of all parts by the number of ways
+// to place the remaining black holes into unconstrained cells.
+func (p *problem) combine(parts []*part) ([][]float64, error) {
+	total := []float64{1}
+	for _, pt := range parts {
+		total = convolve(total, pt.counts)
+	}
+
+	free := len(p.unconstrained)
+
+	// weights are scaled by the largest one to keep the numbers within the float64 range.
+	logWeight := func(k int) float64 {
+		rest := p.remaining - k
+		if rest < 0 || rest > free {
+			return math.Inf(-1)
+		}
+		return lnChoose(free, rest)
+	}
+
+	maxLog := math.Inf(-1)
+	for k, n := range total {
+		if n > 0 && logWeight(k) > maxLog {
+			maxLog = logWeight(k)
+		}
+	}
+
+	if math.IsInf(maxLog, -1) {
+		return nil, ErrInconsistentState
+	}
+
+	weight := func(k int) float64 {
+		return math.Exp(logWeight(k) - maxLog)
+	}
+
+	var z, unconstrainedHoles float64
+
+	for k, n := range total {
+		if n == 0 {
+			continue
+		}
+
+		w := n * weight(k)
+		z += w
+
+		if free > 0 {
+			unconstrainedHoles += w * float64(p.remaining-k) / float64(free)
+		}
+	}
+
+	probabilities := p.emptyMatrix()
+
+	for _, pos := range p.unconstrained {
+		probabilities[pos.Row][pos.Col] = unconstrainedHoles / z
+	}
+
+	for i, pt := range parts {
+		others := []float64{1}
+		for j, other := range parts {
+			if i != j {
+				others = convolve(others, other.counts)
+			}
+		}
+
+		for c, cell := range pt.cells {
+			var holes float64
+
+			for k, byK := range pt.cellCounts {
+				if byK[c] == 0 {
+					continue
+				}
+
+				for ko, n := range others {
+					if n > 0 {
+						holes += byK[c] * n * weight(k+ko)
+					}
+				}
+			}
+
+			pos := p.frontier[cell]
+			probabilities[pos.Row][pos.Col] = holes / z
+		}
+	}
+
+	return probabilities, nil
+}
+
+// approximate estimates the probabilities without enumerating arrangements.
+// A frontier cell gets the average ratio of missing black holes to unknown neighbors of its clues.
+// Unconstrained cells share evenly the black holes that are not expected on the frontier.
+func (p *problem) approximate() [][]float64 {
+	probabilities := p.emptyMatrix()
+
+	var frontierHoles float64
+
+	for cell, pos := range p.frontier {
+		var sum float64
+
+		for _, c := range p.cellClues[cell] {
+			sum += float64(p.constraints[c].need) / float64(len(p.constraints[c].cells))
+		}
+
+		probabilities[pos.Row][pos.Col] = sum / float64(len(p.cellClues[cell]))
+		frontierHoles += probabilities[pos.Row][pos.Col]
+	}
+
+	if len(p.unconstrained) > 0 {
+		chance := math.Max(0, math.Min(1, (float64(p.remaining)-frontierHoles)/float64(len(p.unconstrained))))
+
+		for _, pos := range p.unconstrained {
+			probabilities[pos.Row][pos.Col] = chance
+		}
+	}
+
+	return probabilities
+}
+
+// emptyMatrix returns a matrix of probabilities with only revealed black holes filled in.
+func (p *problem) emptyMatrix() [][]float64 {
+	m := make([][]float64, len(p.state))
+
+	for i, row := range p.state {
+		m[i] = make([]float64, len(row))
+
+		for j, v := range row {
+			if v == board.CellValueBlackHole {
+				m[i][j] = 1
+			}
+		}
+	}
+
+	return m
+}
+
+func convolve(a []float64, b []float64) []float64 {
+	result := make([]float64, len(a)+len(b)-1)
+
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+
+		for j, y := range b {
+			result[i+j] += x * y
+		}
+	}
+
+	return result
+}
+
+// lnChoose returns the natural logarithm of the binomial coefficient "n choose k".
+func lnChoose(n int, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+
+	return a - b - c
+}
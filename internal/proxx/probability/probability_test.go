@@ -0,0 +1,153 @@
+package probability_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/probability"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		state           [][]board.CellValue
+		totalBlackHoles int
+		expected        [][]float64
+	}{
+		{
+			name:            "Single clue and an unconstrained cell",
+			state:           [][]board.CellValue{{"1", "?", "?"}},
+			totalBlackHoles: 1,
+			expected:        [][]float64{{0, 1, 0}},
+		},
+		{
+			name:            "Fifty-fifty on the frontier",
+			state:           [][]board.CellValue{{"?", "1", "?", "?", "?"}},
+			totalBlackHoles: 2,
+			expected:        [][]float64{{0.5, 0, 0.5, 0.5, 0.5}},
+		},
+		{
+			name:            "Total number of black holes decides the arrangement",
+			state:           [][]board.CellValue{{"?", "1", "?", "1", "?"}},
+			totalBlackHoles: 2,
+			expected:        [][]float64{{1, 0, 0, 0, 1}},
+		},
+		{
+			name:            "Arrangements weighted by unconstrained cells",
+			state:           [][]board.CellValue{{"?", "1", "?", "1", "?", "?", "?"}},
+			totalBlackHoles: 2,
+			expected:        [][]float64{{1.0 / 3, 0, 2.0 / 3, 0, 1.0 / 3, 1.0 / 3, 1.0 / 3}},
+		},
+		{
+			name: "Independent parts of the frontier",
+			state: [][]board.CellValue{
+				{"?", "1", "?", "?", "?", "1", "?"},
+			},
+			totalBlackHoles: 2,
+			expected:        [][]float64{{0.5, 0, 0.5, 0, 0.5, 0, 0.5}},
+		},
+		{
+			name:            "Revealed black holes",
+			state:           [][]board.CellValue{{"H", "2", "?", "?"}},
+			totalBlackHoles: 2,
+			expected:        [][]float64{{1, 0, 1, 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := probability.Calculate(tc.state, tc.totalBlackHoles, 0)
+			require.NoError(t, err)
+			assert.True(t, result.Exact)
+
+			require.Len(t, result.Probabilities, len(tc.expected))
+			for i := range tc.expected {
+				assert.InDeltaSlice(t, tc.expected[i], result.Probabilities[i], 1e-9)
+			}
+		})
+	}
+}
+
+func TestCalculate_Inconsistent(t *testing.T) {
+	testCases := []struct {
+		name            string
+		state           [][]board.CellValue
+		totalBlackHoles int
+	}{
+		{name: "Clue needs more cells than available", state: [][]board.CellValue{{"2", "?"}}, totalBlackHoles: 2},
+		{name: "Clue needs more black holes than left", state: [][]board.CellValue{{"1", "?", "?"}}, totalBlackHoles: 0},
+		{name: "Too many revealed black holes", state: [][]board.CellValue{{"H", "H", "?"}}, totalBlackHoles: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := probability.Calculate(tc.state, tc.totalBlackHoles, 0)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCalculate_Budget(t *testing.T) {
+	t.Parallel()
+
+	const width = 60
+
+	state := [][]board.CellValue{
+		stateRow("?", width),
+		stateRow("2", width),
+		stateRow("?", width),
+	}
+
+	result, err := probability.Calculate(state, width, time.Nanosecond)
+	require.NoError(t, err)
+
+	assert.False(t, result.Exact)
+	assert.InDelta(t, 2.0/6.0, result.Probabilities[0][width/2], 1e-9)
+	assert.Zero(t, result.Probabilities[1][width/2])
+}
+
+func TestCalculateSteps(t *testing.T) {
+	t.Parallel()
+
+	const width = 60
+
+	state := [][]board.CellValue{
+		stateRow("?", width),
+		stateRow("2", width),
+		stateRow("?", width),
+	}
+
+	limited, err := probability.CalculateSteps(state, width, 1000)
+	require.NoError(t, err)
+
+	assert.False(t, limited.Exact)
+	assert.InDelta(t, 2.0/6.0, limited.Probabilities[0][width/2], 1e-9)
+
+	small := [][]board.CellValue{{"?", "1", "?", "1", "?", "?", "?"}}
+
+	exact, err := probability.CalculateSteps(small, 2, 1000)
+	require.NoError(t, err)
+
+	expected, err := probability.Calculate(small, 2, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, exact)
+}
+
+func stateRow(v board.CellValue, width int) []board.CellValue {
+	row := make([]board.CellValue, width)
+	for i := range row {
+		row[i] = v
+	}
+
+	return row
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSV writes snapshots as comma-separated raw cell values, a line per row of the board.
+// Snapshots are separated by an empty line.
+type CSV struct{}
+
+func (CSV) Render(w io.Writer, s Snapshot) error {
+	cw := csv.NewWriter(w)
+
+	for _, row := range s.State {
+		record := make([]string, 0, len(row))
+		for _, v := range row {
+			record = append(record, string(v))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write the snapshot: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write the snapshot: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"proxx/internal/proxx/command"
+)
+
+// HTML writes snapshots as standalone HTML documents: the styles are inlined, nothing is loaded from elsewhere.
+// Every cell has the class of its kind, clues have the class of their number as well.
+type HTML struct {
+	Notation command.Notation
+}
+
+var htmlTemplate = template.Must(template.New("board").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Proxx: {{.Status}}</title>
+<style>
+table { border-collapse: collapse; font-family: monospace; font-size: 18px; }
+th { color: #666; padding: 0 6px; }
+td { width: 24px; height: 24px; text-align: center; font-weight: bold; border: 1px solid #999; }
+td.hidden { background: #bbb; }
+td.flag { background: #bbb; color: #d00; }
+td.wrong-flag { background: #fc0; color: #000; text-decoration: line-through; }
+td.black-hole { background: #ddd; color: #609; }
+td.exploded { background: #d00; color: #fff; }
+td.blank, td.clue { background: #eee; }
+td.clue-1 { color: #00f; }
+td.clue-2 { color: #080; }
+td.clue-3 { color: #f00; }
+td.clue-4 { color: #008; }
+td.clue-5 { color: #800; }
+td.clue-6 { color: #088; }
+td.clue-7 { color: #000; }
+td.clue-8 { color: #888; }
+</style>
+</head>
+<body>
+<p>The game is {{.Status}}.</p>
+<table>
+<tr><th></th>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr><th>{{.Label}}</th>{{range .Cells}}<td class="{{.Class}}" title="{{.Position}}">{{.Glyph}}</td>{{end}}</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type htmlBoard struct {
+	Status  string
+	Columns []string
+	Rows    []htmlRow
+}
+
+type htmlRow struct {
+	Label string
+	Cells []htmlCell
+}
+
+type htmlCell struct {
+	Class    string
+	Position string
+	Glyph    string
+}
+
+func (h HTML) Render(w io.Writer, s Snapshot) error {
+	doc := htmlBoard{Status: s.Status.String()}
+
+	for j := 0; j < s.Cols(); j++ {
+		doc.Columns = append(doc.Columns, command.ColumnLetters(j))
+	}
+
+	for i := 0; i < s.Rows(); i++ {
+		row := htmlRow{Label: h.Notation.RowLabel(i)}
+
+		for j := 0; j < s.Cols(); j++ {
+			kind, clue := s.Cell(i, j)
+
+			cell := htmlCell{Class: kind.String(), Position: command.ColumnLetters(j) + h.Notation.RowLabel(i)}
+			if kind == CellClue {
+				cell.Class = fmt.Sprintf("clue clue-%d", clue)
+			}
+
+			if kind != CellBlank && kind != CellHidden {
+				cell.Glyph = ASCII.Glyph(kind, clue)
+			}
+
+			row.Cells = append(row.Cells, cell)
+		}
+
+		doc.Rows = append(doc.Rows, row)
+	}
+
+	if err := htmlTemplate.Execute(w, doc); err != nil {
+		return fmt.Errorf("failed to write the snapshot: %w", err)
+	}
+
+	return nil
+}
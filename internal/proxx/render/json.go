@@ -0,0 +1,38 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/board"
+)
+
+// JSON writes snapshots as JSON documents, one per line, so a stream of snapshots can be read line by line.
+// Cells hold the raw cell values, positions are zero-based pairs [row, col].
+type JSON struct{}
+
+type jsonSnapshot struct {
+	Rows     int                 `json:"rows"`
+	Cols     int                 `json:"cols"`
+	Status   string              `json:"status"`
+	Exploded *[2]int             `json:"exploded,omitempty"`
+	Cells    [][]board.CellValue `json:"cells"`
+}
+
+func (JSON) Render(w io.Writer, s Snapshot) error {
+	doc := jsonSnapshot{Rows: s.Rows(), Cols: s.Cols(), Status: s.Status.String(), Cells: s.State}
+
+	if doc.Cells == nil {
+		doc.Cells = [][]board.CellValue{}
+	}
+
+	if s.Exploded != nil {
+		doc.Exploded = &[2]int{s.Exploded.Row, s.Exploded.Col}
+	}
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode the snapshot: %w", err)
+	}
+
+	return nil
+}
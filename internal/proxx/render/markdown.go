@@ -0,0 +1,42 @@
+package render
+
+import (
+	"io"
+	"proxx/internal/proxx/command"
+	"strings"
+)
+
+// Markdown writes snapshots as Markdown tables: columns are labeled with letters, rows with numbers
+// in the notation, cells are drawn with the theme.
+type Markdown struct {
+	Theme    Theme
+	Notation command.Notation
+}
+
+func (m Markdown) Render(w io.Writer, s Snapshot) error {
+	var b strings.Builder
+
+	b.WriteString("|   |")
+	for j := 0; j < s.Cols(); j++ {
+		b.WriteString(" " + command.ColumnLetters(j) + " |")
+	}
+
+	b.WriteString("\n|---|" + strings.Repeat("---|", s.Cols()) + "\n")
+
+	for i := 0; i < s.Rows(); i++ {
+		b.WriteString("| " + m.Notation.RowLabel(i) + " |")
+
+		for j := 0; j < s.Cols(); j++ {
+			kind, clue := s.Cell(i, j)
+			b.WriteString(" " + m.Theme.Glyph(kind, clue) + " |")
+		}
+
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,164 @@
+package render_test
+
+import (
+	"bytes"
+	"os"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLostGame returns a 3x3 game with black holes at (1,2) and (2,2) lost at (2,2):
+// the flag at (0,0) is wrong, the flag at (1,2) is right.
+func newLostGame(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	require.NoError(t, g.ToggleFlag(0, 0))
+	require.NoError(t, g.ToggleFlag(1, 2))
+
+	_, err = g.OpenCell(2, 2)
+	require.NoError(t, err)
+	require.Equal(t, game.StatusLost, g.Status())
+
+	return g
+}
+
+func TestSnapshot_Cell(t *testing.T) {
+	t.Run("Lost game", func(t *testing.T) {
+		t.Parallel()
+
+		s := render.SnapshotOf(newLostGame(t))
+
+		testCases := []struct {
+			pos      board.Position
+			expected render.CellKind
+		}{
+			{pos: board.Position{Row: 0, Col: 0}, expected: render.CellWrongFlag},
+			{pos: board.Position{Row: 0, Col: 1}, expected: render.CellHidden},
+			{pos: board.Position{Row: 1, Col: 2}, expected: render.CellBlackHole},
+			{pos: board.Position{Row: 2, Col: 2}, expected: render.CellExploded},
+		}
+
+		for _, tc := range testCases {
+			kind, _ := s.Cell(tc.pos.Row, tc.pos.Col)
+			assert.Equal(t, tc.expected, kind, "cell %v", tc.pos)
+		}
+	})
+
+	t.Run("Game in progress", func(t *testing.T) {
+		t.Parallel()
+
+		s := render.Snapshot{State: [][]board.CellValue{{"F", "0", "3"}}}
+
+		kind, _ := s.Cell(0, 0)
+		assert.Equal(t, render.CellFlag, kind)
+
+		kind, _ = s.Cell(0, 1)
+		assert.Equal(t, render.CellBlank, kind)
+
+		kind, clue := s.Cell(0, 2)
+		assert.Equal(t, render.CellClue, kind)
+		assert.Equal(t, 3, clue)
+	})
+}
+
+func TestThemeByName(t *testing.T) {
+	for _, theme := range render.Themes() {
+		found, err := render.ThemeByName(strings.ToUpper(theme.Name))
+		require.NoError(t, err)
+		assert.Equal(t, theme.Name, found.Name)
+	}
+
+	_, err := render.ThemeByName("neon")
+	assert.ErrorIs(t, err, render.ErrUnknownTheme)
+}
+
+func TestTerminal_Render(t *testing.T) {
+	testCases := []struct {
+		name     string
+		renderer render.Terminal
+		expected string
+	}{
+		{
+			name:     "ASCII",
+			renderer: render.Terminal{Theme: render.ASCII},
+			expected: "   A B C\n" +
+				"   1 2 3\n" +
+				"1  X ? ?\n" +
+				"2  ? ? H\n" +
+				"3  ? ? *\n",
+		},
+		{
+			name:     "Unicode with a frame and zero-based notation",
+			renderer: render.Terminal{Theme: render.Unicode, Notation: command.Notation{ZeroBased: true}},
+			expected: "    A B C\n" +
+				"    0 1 2\n" +
+				"  ┌───────┐\n" +
+				"0 │ ✗ ■ ■ │\n" +
+				"1 │ ■ ■ ● │\n" +
+				"2 │ ■ ■ ✹ │\n" +
+				"  └───────┘\n",
+		},
+		{
+			name:     "Emoji",
+			renderer: render.Terminal{Theme: render.Emoji},
+			expected: "    A  B  C\n" +
+				"    1  2  3\n" +
+				"1  ❌ 🟦 🟦\n" +
+				"2  🟦 🟦 ⚫\n" +
+				"3  🟦 🟦 💥\n",
+		},
+		{
+			name:     "Colors",
+			renderer: render.Terminal{Theme: render.ASCII, Color: true},
+			expected: "   A B C\n" +
+				"   1 2 3\n" +
+				"1  \x1b[1;30;43mX\x1b[0m \x1b[2m?\x1b[0m \x1b[2m?\x1b[0m\n" +
+				"2  \x1b[2m?\x1b[0m \x1b[2m?\x1b[0m \x1b[1;35mH\x1b[0m\n" +
+				"3  \x1b[2m?\x1b[0m \x1b[2m?\x1b[0m \x1b[1;97;41m*\x1b[0m\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+
+			require.NoError(t, tc.renderer.Render(&out, render.SnapshotOf(newLostGame(t))))
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestTerminal_Cell(t *testing.T) {
+	s := render.Snapshot{State: [][]board.CellValue{{"1", "2", "7", "0"}}}
+	r := render.Terminal{Theme: render.ASCII, Color: true}
+
+	assert.Equal(t, "  \x1b[94m1\x1b[0m", r.Cell(s, 0, 0, 3))
+	assert.Equal(t, "\x1b[32m2\x1b[0m", r.Cell(s, 0, 1, 1))
+	assert.Equal(t, "\x1b[1m7\x1b[0m", r.Cell(s, 0, 2, 1))
+	assert.Equal(t, ".", r.Cell(s, 0, 3, 1))
+}
+
+func TestColorEnabled(t *testing.T) {
+	assert.False(t, render.ColorEnabled(&bytes.Buffer{}))
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, render.IsTerminal(f))
+	assert.False(t, render.ColorEnabled(f))
+}
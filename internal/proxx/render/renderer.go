@@ -0,0 +1,51 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/command"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Formats of snapshots.
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatHTML     = "html"
+)
+
+// Renderer is the interface that wraps the Render method.
+//
+// Render writes the snapshot of a board to w.
+type Renderer interface {
+	Render(w io.Writer, s Snapshot) error
+}
+
+// Formats returns the names of the formats supported by NewRenderer.
+func Formats() []string {
+	return []string{FormatText, FormatJSON, FormatMarkdown, FormatCSV, FormatHTML}
+}
+
+// NewRenderer creates a renderer of the specified format, the name is case-insensitive.
+// Labels of rows and columns are written in the notation, text is drawn with the ASCII theme and no colors.
+func NewRenderer(format string, n command.Notation) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case FormatText:
+		return Terminal{Theme: ASCII, Notation: n}, nil
+	case FormatJSON:
+		return JSON{}, nil
+	case FormatMarkdown:
+		return Markdown{Theme: ASCII, Notation: n}, nil
+	case FormatCSV:
+		return CSV{}, nil
+	case FormatHTML:
+		return HTML{Notation: n}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,102 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/command"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/render"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// assertGolden compares the output with the golden file in testdata, the file is rewritten with -update.
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual))
+}
+
+// newGameInProgress returns a 3x3 game with black holes at (1,2) and (2,2) after the cell (0,0) is opened.
+func newGameInProgress(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	_, err = g.OpenCell(0, 0)
+	require.NoError(t, err)
+	require.NoError(t, g.ToggleFlag(1, 2))
+
+	return g
+}
+
+func TestRenderers(t *testing.T) {
+	snapshots := []struct {
+		name     string
+		snapshot func(t *testing.T) render.Snapshot
+	}{
+		{name: "lost", snapshot: func(t *testing.T) render.Snapshot { return render.SnapshotOf(newLostGame(t)) }},
+		{name: "in-progress", snapshot: func(t *testing.T) render.Snapshot { return render.SnapshotOf(newGameInProgress(t)) }},
+		{name: "debug", snapshot: func(t *testing.T) render.Snapshot { return render.DebugSnapshotOf(newGameInProgress(t)) }},
+	}
+
+	extensions := map[string]string{
+		render.FormatText:     "txt",
+		render.FormatJSON:     "json",
+		render.FormatMarkdown: "md",
+		render.FormatCSV:      "csv",
+		render.FormatHTML:     "html",
+	}
+
+	for _, format := range render.Formats() {
+		r, err := render.NewRenderer(format, command.DefaultNotation)
+		require.NoError(t, err)
+
+		for _, sc := range snapshots {
+			name := sc.name + "." + extensions[format]
+
+			t.Run(name, func(t *testing.T) {
+				var out bytes.Buffer
+
+				require.NoError(t, r.Render(&out, sc.snapshot(t)))
+				assertGolden(t, name, out.Bytes())
+			})
+		}
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	r, err := render.NewRenderer("JSON", command.DefaultNotation)
+	require.NoError(t, err)
+	assert.IsType(t, render.JSON{}, r)
+
+	_, err = render.NewRenderer("yaml", command.DefaultNotation)
+	assert.ErrorIs(t, err, render.ErrUnknownFormat)
+}
+
+func TestDebugSnapshotOf(t *testing.T) {
+	g := newGameInProgress(t)
+
+	// the player's snapshot hides the black holes, the debug one doesn't
+	assert.EqualValues(t, board.CellValueFlag, render.SnapshotOf(g).State[1][2])
+	assert.EqualValues(t, board.CellValueBlackHole, render.DebugSnapshotOf(g).State[1][2])
+	assert.EqualValues(t, board.CellValueUnknown, render.SnapshotOf(g).State[2][2])
+}
@@ -0,0 +1,120 @@
+// Package render draws game boards for front ends: it tells apart the kinds of cells
+// a player should see and draws them with themes and colors.
+package render
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+)
+
+// CellKind represents the way a cell looks to a player.
+type CellKind int
+
+const (
+	CellHidden CellKind = iota
+	CellFlag
+	// CellWrongFlag is a flag on a cell without a black hole, it's shown once the game is lost.
+	CellWrongFlag
+	CellBlackHole
+	// CellExploded is the black hole that ended the game.
+	CellExploded
+	CellBlank
+	CellClue
+)
+
+func (k CellKind) String() string {
+	switch k {
+	case CellHidden:
+		return "hidden"
+	case CellFlag:
+		return "flag"
+	case CellWrongFlag:
+		return "wrong-flag"
+	case CellBlackHole:
+		return "black-hole"
+	case CellExploded:
+		return "exploded"
+	case CellBlank:
+		return "blank"
+	case CellClue:
+		return "clue"
+	default:
+		return "unknown"
+	}
+}
+
+// Snapshot represents what a player may see of a game at some moment.
+type Snapshot struct {
+	State  [][]board.CellValue
+	Status game.Status
+	// Exploded is the position of the black hole that ended the game, it's set only if the game is lost.
+	Exploded *board.Position
+}
+
+// SnapshotOf takes the snapshot of the game. Hidden cells stay hidden.
+func SnapshotOf(g *game.Game) Snapshot {
+	s := Snapshot{State: g.BoardState(), Status: g.Status()}
+
+	if pos, ok := g.LostAt(); ok {
+		s.Exploded = &pos
+	}
+
+	return s
+}
+
+// DebugSnapshotOf takes the snapshot of the game with all the cells revealed.
+// It's intended for debugging and analyzing games, never show it to a player.
+func DebugSnapshotOf(g *game.Game) Snapshot {
+	s := SnapshotOf(g)
+	s.State = g.DebugState()
+
+	return s
+}
+
+// Rows returns the number of rows on the board.
+func (s Snapshot) Rows() int {
+	return len(s.State)
+}
+
+// Cols returns the number of columns on the board.
+func (s Snapshot) Cols() int {
+	if len(s.State) == 0 {
+		return 0
+	}
+
+	return len(s.State[0])
+}
+
+// Cell returns the kind of the cell and its clue if the cell is a clue.
+// All the black holes are open once the game is lost, so a flag left on a closed cell is a wrong one.
+func (s Snapshot) Cell(row int, col int) (CellKind, int) {
+	v := s.State[row][col]
+
+	switch v {
+	case board.CellValueUnknown:
+		return CellHidden, 0
+	case board.CellValueFlag:
+		if s.Status == game.StatusLost {
+			return CellWrongFlag, 0
+		}
+
+		return CellFlag, 0
+	case board.CellValueBlackHole:
+		if s.Exploded != nil && *s.Exploded == (board.Position{Row: row, Col: col}) {
+			return CellExploded, 0
+		}
+
+		return CellBlackHole, 0
+	}
+
+	clue, ok := v.Clue()
+
+	switch {
+	case !ok:
+		return CellHidden, 0
+	case clue == 0:
+		return CellBlank, 0
+	default:
+		return CellClue, clue
+	}
+}
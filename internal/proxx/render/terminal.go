@@ -0,0 +1,143 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"proxx/internal/proxx/command"
+	"strings"
+)
+
+// clueColors holds the classic colors of the clues from 1 to 8 as SGR parameters:
+// blue, green, red, navy, maroon, teal, black and gray.
+// Black is drawn bold in the default color, so the 7 stays readable on dark terminals.
+var clueColors = [9]string{"", "94", "32", "91", "34", "31", "36", "1", "90"}
+
+// cellColors holds the colors of the other kinds of cells as SGR parameters.
+var cellColors = map[CellKind]string{
+	CellHidden:    "2",
+	CellFlag:      "1;93",
+	CellWrongFlag: "1;30;43",
+	CellBlackHole: "1;35",
+	CellExploded:  "1;97;41",
+}
+
+// Terminal draws boards as text for a terminal.
+// Glyphs come from the theme, colors are written as ANSI escape sequences if Color is true.
+// Rows and columns are labeled in the notation.
+type Terminal struct {
+	Theme    Theme
+	Color    bool
+	Notation command.Notation
+}
+
+// NewTerminal creates a renderer that draws the board for w with the theme.
+// Colors are used only if w is a terminal that accepts them.
+func NewTerminal(w io.Writer, theme Theme, n command.Notation) Terminal {
+	return Terminal{Theme: theme, Color: ColorEnabled(w), Notation: n}
+}
+
+// ColorEnabled returns true if colors may be written to w: it must be a terminal,
+// the NO_COLOR environment variable (https://no-color.org) must be empty and TERM must not be "dumb".
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return IsTerminal(w)
+}
+
+// IsTerminal returns true if w is a file that refers to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Cell returns the cell drawn with the theme and aligned to the right within width columns.
+func (t Terminal) Cell(s Snapshot, row int, col int, width int) string {
+	kind, clue := s.Cell(row, col)
+	glyph := t.Theme.Glyph(kind, clue)
+
+	if t.Color {
+		color := cellColors[kind]
+		if kind == CellClue {
+			color = clueColors[clue]
+		}
+
+		if color != "" {
+			glyph = "\x1b[" + color + "m" + glyph + "\x1b[0m"
+		}
+	}
+
+	return strings.Repeat(" ", max(0, width-t.Theme.Width)) + glyph
+}
+
+// Render writes the board with the labels of columns (letters and numbers) and rows,
+// so a position can be read straight off the screen.
+func (t Terminal) Render(w io.Writer, s Snapshot) error {
+	rows, cols := s.Rows(), s.Cols()
+
+	labelWidth := 0
+	if rows > 0 {
+		labelWidth = len(t.Notation.RowLabel(rows - 1))
+	}
+
+	cellWidth := t.Theme.Width
+	if cols > 0 {
+		cellWidth = max(cellWidth, len(command.ColumnLetters(cols-1)), len(t.Notation.RowLabel(cols-1)))
+	}
+
+	indent := labelWidth + 1
+	if t.Theme.Frame {
+		indent++
+	}
+
+	var b strings.Builder
+
+	letters := strings.Repeat(" ", indent)
+	numbers := strings.Repeat(" ", indent)
+
+	for j := 0; j < cols; j++ {
+		letters += fmt.Sprintf(" %*s", cellWidth, command.ColumnLetters(j))
+		numbers += fmt.Sprintf(" %*s", cellWidth, t.Notation.RowLabel(j))
+	}
+
+	b.WriteString(letters + "\n" + numbers + "\n")
+
+	border := strings.Repeat("─", cols*(cellWidth+1)+1)
+	if t.Theme.Frame {
+		b.WriteString(strings.Repeat(" ", labelWidth+1) + "┌" + border + "┐\n")
+	}
+
+	for i := 0; i < rows; i++ {
+		b.WriteString(fmt.Sprintf("%*s ", labelWidth, t.Notation.RowLabel(i)))
+
+		if t.Theme.Frame {
+			b.WriteString("│")
+		}
+
+		for j := 0; j < cols; j++ {
+			b.WriteString(" " + t.Cell(s, i, j, cellWidth))
+		}
+
+		if t.Theme.Frame {
+			b.WriteString(" │")
+		}
+
+		b.WriteString("\n")
+	}
+
+	if t.Theme.Frame {
+		b.WriteString(strings.Repeat(" ", labelWidth+1) + "└" + border + "┘\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
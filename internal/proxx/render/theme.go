@@ -0,0 +1,108 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Theme defines the glyphs used to draw the cells of a board.
+// All the glyphs of a theme take Width columns of a terminal.
+type Theme struct {
+	Name      string
+	Hidden    string
+	Flag      string
+	WrongFlag string
+	BlackHole string
+	Exploded  string
+	Blank     string
+	// Clues holds the glyphs of the clues from 1 to 8, Clues[0] is unused.
+	Clues [9]string
+	Width int
+	// Frame is true if the board is framed with box-drawing characters.
+	Frame bool
+}
+
+var (
+	// ASCII is the theme that works everywhere: digits for clues and letters for everything else.
+	ASCII = Theme{
+		Name:      "ascii",
+		Hidden:    "?",
+		Flag:      "F",
+		WrongFlag: "X",
+		BlackHole: "H",
+		Exploded:  "*",
+		Blank:     ".",
+		Clues:     [9]string{"", "1", "2", "3", "4", "5", "6", "7", "8"},
+		Width:     1,
+	}
+
+	// Unicode draws cells with geometric shapes and frames the board with box-drawing characters.
+	Unicode = Theme{
+		Name:      "unicode",
+		Hidden:    "■",
+		Flag:      "⚑",
+		WrongFlag: "✗",
+		BlackHole: "●",
+		Exploded:  "✹",
+		Blank:     "·",
+		Clues:     [9]string{"", "1", "2", "3", "4", "5", "6", "7", "8"},
+		Width:     1,
+		Frame:     true,
+	}
+
+	// Emoji draws cells with emoji, clues are fullwidth digits to keep the columns aligned.
+	Emoji = Theme{
+		Name:      "emoji",
+		Hidden:    "🟦",
+		Flag:      "🚩",
+		WrongFlag: "❌",
+		BlackHole: "⚫",
+		Exploded:  "💥",
+		Blank:     "⬜",
+		Clues:     [9]string{"", "１", "２", "３", "４", "５", "６", "７", "８"},
+		Width:     2,
+	}
+)
+
+// Themes returns the built-in themes.
+func Themes() []Theme {
+	return []Theme{ASCII, Unicode, Emoji}
+}
+
+// ThemeByName returns the built-in theme with the specified name, the name is case-insensitive.
+func ThemeByName(name string) (Theme, error) {
+	for _, t := range Themes() {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+
+	return Theme{}, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+}
+
+// Glyph returns the glyph of the cell kind, clue is used only for clues.
+func (t Theme) Glyph(kind CellKind, clue int) string {
+	switch kind {
+	case CellFlag:
+		return t.Flag
+	case CellWrongFlag:
+		return t.WrongFlag
+	case CellBlackHole:
+		return t.BlackHole
+	case CellExploded:
+		return t.Exploded
+	case CellBlank:
+		return t.Blank
+	case CellClue:
+		if clue > 0 && clue < len(t.Clues) {
+			return t.Clues[clue]
+		}
+
+		return t.Hidden
+	default:
+		return t.Hidden
+	}
+}
@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/game"
+)
+
+var (
+	ErrNoNextMove     = errors.New("no next move")
+	ErrNoPreviousMove = errors.New("no previous move")
+)
+
+// Player plays a replay back move by move in both directions.
+type Player struct {
+	replay Replay
+	game   *game.Game
+	step   int
+}
+
+// NewPlayer returns a player positioned before the first move.
+func NewPlayer(r Replay) (*Player, error) {
+	g, err := r.NewGame()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a game: %w", err)
+	}
+
+	return &Player{replay: r, game: g}, nil
+}
+
+// Game returns the game with the moves played so far.
+// Don't make moves on it, use Forward, Backward and Seek instead.
+func (p *Player) Game() *game.Game {
+	return p.game
+}
+
+// Step returns the number of moves played so far.
+func (p *Player) Step() int {
+	return p.step
+}
+
+// Len returns the number of moves in the replay.
+func (p *Player) Len() int {
+	return len(p.replay.Moves)
+}
+
+// Next returns the move that will be played by Forward.
+func (p *Player) Next() (game.Move, error) {
+	if p.step >= p.Len() {
+		return game.Move{}, ErrNoNextMove
+	}
+
+	return p.replay.Moves[p.step], nil
+}
+
+// Last returns the last played move as it was recorded, with its elapsed time.
+func (p *Player) Last() (game.Move, error) {
+	if p.step == 0 {
+		return game.Move{}, ErrNoPreviousMove
+	}
+
+	return p.replay.Moves[p.step-1], nil
+}
+
+// Forward plays the next move and returns it.
+func (p *Player) Forward() (game.Move, error) {
+	m, err := p.Next()
+	if err != nil {
+		return game.Move{}, err
+	}
+
+	if err := p.game.Apply(m); err != nil {
+		return game.Move{}, fmt.Errorf("%w: move #%d: %w", ErrInvalidMove, p.step+1, err)
+	}
+
+	p.step++
+
+	return m, nil
+}
+
+// Backward takes the last played move back.
+func (p *Player) Backward() error {
+	if p.step == 0 {
+		return ErrNoPreviousMove
+	}
+
+	return p.Seek(p.step - 1)
+}
+
+// Seek positions the player after the specified number of moves.
+// The game is rebuilt from scratch, so seeking backwards is as cheap as replaying the moves.
+func (p *Player) Seek(step int) error {
+	if step < 0 || step > p.Len() {
+		return fmt.Errorf("step %d is out of range [0, %d]", step, p.Len())
+	}
+
+	if step < p.step {
+		g, err := p.replay.NewGame()
+		if err != nil {
+			return fmt.Errorf("failed to create a game: %w", err)
+		}
+
+		p.game = g
+		p.step = 0
+	}
+
+	for p.step < step {
+		if _, err := p.Forward(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package replay_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/replay"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayer(t *testing.T) {
+	g := newFixedGame(t)
+
+	require.NoError(t, g.ToggleFlag(1, 2))
+	_, err := g.OpenCell(2, 0)
+	require.NoError(t, err)
+	_, err = g.OpenCell(0, 2)
+	require.NoError(t, err)
+
+	p, err := replay.NewPlayer(replay.Record(g))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, p.Len())
+	assert.EqualValues(t, 0, p.Step())
+	assert.ErrorIs(t, p.Backward(), replay.ErrNoPreviousMove)
+
+	_, err = p.Last()
+	assert.ErrorIs(t, err, replay.ErrNoPreviousMove)
+
+	m, err := p.Forward()
+	require.NoError(t, err)
+	assert.EqualValues(t, game.MoveToggleFlag, m.Kind)
+
+	last, err := p.Last()
+	require.NoError(t, err)
+	assert.Equal(t, replay.Record(g).Moves[0], last)
+
+	_, err = p.Forward()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, [][]board.CellValue{
+		{"0", "1", "?"},
+		{"0", "2", "F"},
+		{"0", "2", "?"},
+	}, p.Game().BoardState())
+
+	require.NoError(t, p.Backward())
+	assert.EqualValues(t, 1, p.Step())
+	assert.EqualValues(t, [][]board.CellValue{
+		{"?", "?", "?"},
+		{"?", "?", "F"},
+		{"?", "?", "?"},
+	}, p.Game().BoardState())
+
+	require.NoError(t, p.Seek(3))
+	assert.True(t, p.Game().IsWon())
+
+	_, err = p.Forward()
+	assert.ErrorIs(t, err, replay.ErrNoNextMove)
+}
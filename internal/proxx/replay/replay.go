@@ -0,0 +1,264 @@
+// Package replay records games and plays them back.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"time"
+)
+
+// FormatVersion is the version of the replay document.
+// It must be increased on every incompatible change of the document.
+const FormatVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported version of the replay")
+	ErrNoLayout           = errors.New("replay has neither a seed nor black hole positions")
+	ErrInvalidMove        = errors.New("replay contains an invalid move")
+	ErrResultMismatch     = errors.New("recorded result doesn't match the replayed game")
+)
+
+// Replay represents a recorded game: the board, the way black holes were placed on it and the moves.
+// If Seed is set, black holes are placed by the locator named Locator with that seed,
+// otherwise they are placed at BlackHoles.
+type Replay struct {
+	Rows           int
+	Cols           int
+	NumBlackHoles  int
+	FirstClickSafe bool
+	Locator        string
+	Seed           *int64
+	BlackHoles     []board.Position
+	Moves          []game.Move
+	Result         game.Status
+}
+
+// Record makes a replay of the game. The seed is recorded if the game's locator is seeded and known
+// and the moves made on the board it gives end with the same black holes. The positions of black holes
+// are recorded otherwise, e.g. when the black hole under an undone first click was moved away.
+func Record(g *game.Game) Replay {
+	cfg := g.Config()
+
+	r := Replay{
+		Rows:           cfg.NumRows,
+		Cols:           cfg.NumCols,
+		NumBlackHoles:  cfg.NumBlackHoles,
+		FirstClickSafe: cfg.FirstClickSafe,
+		Moves:          g.History(),
+		Result:         g.Status(),
+	}
+
+	if name, seed, seeded := g.Locator(); seeded {
+		r.Locator = name
+		r.Seed = &seed
+
+		if r.reproduces(g.BlackHoles()) {
+			return r
+		}
+
+		r.Locator = ""
+		r.Seed = nil
+	}
+
+	r.BlackHoles = g.BlackHoles()
+
+	return r
+}
+
+// reproduces checks that the moves made on a new game end with the black holes at the positions.
+func (r Replay) reproduces(blackHoles []board.Position) bool {
+	g, err := r.NewGame()
+	if err != nil {
+		return false
+	}
+
+	for _, m := range r.Moves {
+		if err := g.Apply(m); err != nil {
+			return false
+		}
+	}
+
+	replayed := g.BlackHoles()
+	if len(replayed) != len(blackHoles) {
+		return false
+	}
+
+	positions := make(map[board.Position]bool, len(blackHoles))
+	for _, p := range blackHoles {
+		positions[p] = true
+	}
+
+	for _, p := range replayed {
+		if !positions[p] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewGame creates a new game with the recorded board and black holes, no moves are made.
+func (r Replay) NewGame() (*game.Game, error) {
+	cfg := game.Config{
+		NumRows:        r.Rows,
+		NumCols:        r.Cols,
+		NumBlackHoles:  r.NumBlackHoles,
+		FirstClickSafe: r.FirstClickSafe,
+	}
+
+	switch {
+	case r.Seed != nil:
+		locator, err := game.NewBlackHoleLocator(r.Locator, *r.Seed)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.BlackHoleLocator = locator
+	case r.BlackHoles != nil:
+		cfg.BlackHoleLocator = game.NewFixedBlackHoleLocator(r.BlackHoles)
+	default:
+		return nil, ErrNoLayout
+	}
+
+	return game.NewGame(cfg)
+}
+
+// Check re-runs the recorded moves on a fresh game and confirms that every move is valid
+// and the game ends with the recorded result.
+func (r Replay) Check() error {
+	g, err := r.NewGame()
+	if err != nil {
+		return fmt.Errorf("failed to create a game: %w", err)
+	}
+
+	for i, m := range r.Moves {
+		if err := g.Apply(m); err != nil {
+			return fmt.Errorf("%w: move #%d (%s at %v): %w", ErrInvalidMove, i+1, m.Kind, m.Position, err)
+		}
+	}
+
+	if g.Status() != r.Result {
+		return fmt.Errorf("%w: recorded %q, replayed %q", ErrResultMismatch, r.Result, g.Status())
+	}
+
+	return nil
+}
+
+// document represents a replay as a JSON document.
+type document struct {
+	Version        int          `json:"version"`
+	Rows           int          `json:"rows"`
+	Cols           int          `json:"cols"`
+	NumBlackHoles  int          `json:"num_black_holes"`
+	FirstClickSafe bool         `json:"first_click_safe,omitempty"`
+	Locator        string       `json:"locator,omitempty"`
+	Seed           *int64       `json:"seed,omitempty"`
+	BlackHoles     []position   `json:"black_holes,omitempty"`
+	Moves          []moveRecord `json:"moves"`
+	Result         string       `json:"result"`
+}
+
+// position represents a position as a pair [row, col].
+type position [2]int
+
+type moveRecord struct {
+	Kind     string   `json:"kind"`
+	Position position `json:"pos"`
+	AtMs     int64    `json:"at_ms"`
+}
+
+// Write writes the replay to w as a versioned JSON document.
+func (r Replay) Write(w io.Writer) error {
+	doc := document{
+		Version:        FormatVersion,
+		Rows:           r.Rows,
+		Cols:           r.Cols,
+		NumBlackHoles:  r.NumBlackHoles,
+		FirstClickSafe: r.FirstClickSafe,
+		Locator:        r.Locator,
+		Seed:           r.Seed,
+		Moves:          make([]moveRecord, 0, len(r.Moves)),
+		Result:         r.Result.String(),
+	}
+
+	for _, p := range r.BlackHoles {
+		doc.BlackHoles = append(doc.BlackHoles, position{p.Row, p.Col})
+	}
+
+	for _, m := range r.Moves {
+		doc.Moves = append(doc.Moves, moveRecord{
+			Kind:     m.Kind.String(),
+			Position: position{m.Position.Row, m.Position.Col},
+			AtMs:     m.Elapsed.Milliseconds(),
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode the replay: %w", err)
+	}
+
+	return nil
+}
+
+// Read reads a replay written by Write from r.
+func Read(r io.Reader) (Replay, error) {
+	var doc document
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Replay{}, fmt.Errorf("failed to decode the replay: %w", err)
+	}
+
+	if doc.Version != FormatVersion {
+		return Replay{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, doc.Version)
+	}
+
+	rp := Replay{
+		Rows:           doc.Rows,
+		Cols:           doc.Cols,
+		NumBlackHoles:  doc.NumBlackHoles,
+		FirstClickSafe: doc.FirstClickSafe,
+		Locator:        doc.Locator,
+		Seed:           doc.Seed,
+		Moves:          make([]game.Move, 0, len(doc.Moves)),
+	}
+
+	for _, p := range doc.BlackHoles {
+		rp.BlackHoles = append(rp.BlackHoles, board.Position{Row: p[0], Col: p[1]})
+	}
+
+	for i, mr := range doc.Moves {
+		kind, err := game.ParseMoveKind(mr.Kind)
+		if err != nil {
+			return Replay{}, fmt.Errorf("move #%d: %w", i+1, err)
+		}
+
+		rp.Moves = append(rp.Moves, game.Move{
+			Kind:     kind,
+			Position: board.Position{Row: mr.Position[0], Col: mr.Position[1]},
+			Elapsed:  time.Duration(mr.AtMs) * time.Millisecond,
+		})
+	}
+
+	result, err := parseStatus(doc.Result)
+	if err != nil {
+		return Replay{}, err
+	}
+
+	rp.Result = result
+
+	return rp, nil
+}
+
+func parseStatus(s string) (game.Status, error) {
+	for _, st := range []game.Status{game.StatusInProgress, game.StatusWon, game.StatusLost} {
+		if st.String() == s {
+			return st, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown game result %q", s)
+}
@@ -0,0 +1,217 @@
+package replay_test
+
+import (
+	"bytes"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/replay"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type predefinedBlackHoleLocator struct {
+	positions []board.Position
+}
+
+func (p predefinedBlackHoleLocator) LocateBlackHolesOnBoard(_ int, _ int, _ int) []board.Position {
+	return p.positions
+}
+
+func newFixedGame(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: predefinedBlackHoleLocator{positions: []board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}},
+	})
+	require.NoError(t, err)
+
+	return g
+}
+
+func TestRecord(t *testing.T) {
+	t.Run("Game with a seeded locator", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{
+			NumRows:          5,
+			NumCols:          5,
+			NumBlackHoles:    5,
+			BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(42),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, g.ToggleFlag(0, 0))
+		_, err = g.OpenCell(4, 4)
+		require.NoError(t, err)
+
+		r := replay.Record(g)
+		require.NotNil(t, r.Seed)
+		assert.EqualValues(t, 42, *r.Seed)
+		assert.EqualValues(t, game.UniformLocatorName, r.Locator)
+		assert.Nil(t, r.BlackHoles)
+		assert.Len(t, r.Moves, 2)
+		assert.EqualValues(t, g.Status(), r.Result)
+
+		assert.NoError(t, r.Check())
+	})
+
+	t.Run("Undone first click that moved a black hole", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{
+			NumRows:          5,
+			NumCols:          5,
+			NumBlackHoles:    5,
+			FirstClickSafe:   true,
+			BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(42),
+		})
+		require.NoError(t, err)
+
+		hole := g.BlackHoles()[0]
+		_, err = g.OpenCell(hole.Row, hole.Col)
+		require.NoError(t, err)
+		require.NoError(t, g.Undo())
+
+		r := replay.Record(g)
+		assert.Nil(t, r.Seed)
+		assert.ElementsMatch(t, g.BlackHoles(), r.BlackHoles)
+
+		replayed, err := r.NewGame()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, g.BlackHoles(), replayed.BlackHoles())
+	})
+
+	t.Run("Loaded game with a safe first click", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(game.Config{
+			NumRows:          5,
+			NumCols:          5,
+			NumBlackHoles:    5,
+			FirstClickSafe:   true,
+			BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(42),
+		})
+		require.NoError(t, err)
+
+		hole := g.BlackHoles()[0]
+		_, err = g.OpenCell(hole.Row, hole.Col)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, g.Save(&buf))
+
+		loaded, err := game.Load(&buf)
+		require.NoError(t, err)
+
+		r := replay.Record(loaded)
+		assert.True(t, r.FirstClickSafe)
+		assert.NoError(t, r.Check())
+
+		replayed, err := r.NewGame()
+		require.NoError(t, err)
+
+		for _, m := range r.Moves {
+			require.NoError(t, replayed.Apply(m))
+		}
+
+		assert.ElementsMatch(t, g.BlackHoles(), replayed.BlackHoles())
+	})
+
+	t.Run("Game with an unknown locator", func(t *testing.T) {
+		t.Parallel()
+
+		g := newFixedGame(t)
+
+		_, err := g.OpenCell(2, 0)
+		require.NoError(t, err)
+		_, err = g.OpenCell(0, 2)
+		require.NoError(t, err)
+
+		r := replay.Record(g)
+		assert.Nil(t, r.Seed)
+		assert.ElementsMatch(t, []board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}, r.BlackHoles)
+		assert.EqualValues(t, game.StatusWon, r.Result)
+
+		assert.NoError(t, r.Check())
+	})
+}
+
+func TestReplay_WriteAndRead(t *testing.T) {
+	g := newFixedGame(t)
+
+	require.NoError(t, g.ToggleFlag(1, 2))
+	_, err := g.OpenCell(2, 2)
+	require.NoError(t, err)
+
+	r := replay.Record(g)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+
+	read, err := replay.Read(&buf)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, r.Rows, read.Rows)
+	assert.EqualValues(t, r.Cols, read.Cols)
+	assert.EqualValues(t, r.NumBlackHoles, read.NumBlackHoles)
+	assert.EqualValues(t, r.BlackHoles, read.BlackHoles)
+	assert.EqualValues(t, game.StatusLost, read.Result)
+	require.Len(t, read.Moves, 2)
+	assert.EqualValues(t, game.MoveToggleFlag, read.Moves[0].Kind)
+	assert.EqualValues(t, board.Position{Row: 2, Col: 2}, read.Moves[1].Position)
+
+	assert.NoError(t, read.Check())
+}
+
+func TestReplay_Check(t *testing.T) {
+	g := newFixedGame(t)
+
+	_, err := g.OpenCell(2, 0)
+	require.NoError(t, err)
+
+	t.Run("Resigned game", func(t *testing.T) {
+		t.Parallel()
+
+		g := newFixedGame(t)
+		_, err := g.OpenCell(0, 0)
+		require.NoError(t, err)
+		require.NoError(t, g.Resign())
+
+		r := replay.Record(g)
+
+		assert.Equal(t, game.StatusLost, r.Result)
+		assert.NoError(t, r.Check())
+	})
+
+	t.Run("Result mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		r := replay.Record(g)
+		r.Result = game.StatusWon
+
+		assert.ErrorIs(t, r.Check(), replay.ErrResultMismatch)
+	})
+
+	t.Run("Invalid move", func(t *testing.T) {
+		t.Parallel()
+
+		r := replay.Record(g)
+		r.Moves = append(r.Moves, r.Moves[0])
+
+		assert.ErrorIs(t, r.Check(), replay.ErrInvalidMove)
+	})
+
+	t.Run("No layout", func(t *testing.T) {
+		t.Parallel()
+
+		r := replay.Record(g)
+		r.BlackHoles = nil
+
+		assert.ErrorIs(t, r.Check(), replay.ErrNoLayout)
+	})
+}
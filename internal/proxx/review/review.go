@@ -0,0 +1,228 @@
+// Package review analyzes the moves of a finished game: every move is replayed and judged
+// by what could be deduced from the board the player saw at that moment.
+package review
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/probability"
+	"proxx/internal/proxx/replay"
+	"proxx/internal/proxx/solver"
+)
+
+// maxProbabilitySteps limits the enumeration of black hole arrangements for a move.
+// When it's reached the chances are estimated, so the review gives the same result on every machine.
+const maxProbabilitySteps = 1 << 20
+
+// Verdict represents the judgment of a move.
+type Verdict int
+
+const (
+	// VerdictSafe means every cell opened by the move was proved free from black holes.
+	VerdictSafe Verdict = iota
+	// VerdictGuess means the move was risky, but no cell could be proved safe at that moment.
+	VerdictGuess
+	// VerdictBlunder means the move was risky while a cell proved safe was available.
+	VerdictBlunder
+	// VerdictFlag means the move only put or removed a flag, flags are never judged.
+	VerdictFlag
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictSafe:
+		return "safe"
+	case VerdictGuess:
+		return "guess"
+	case VerdictBlunder:
+		return "blunder"
+	case VerdictFlag:
+		return "flag"
+	default:
+		return "unknown"
+	}
+}
+
+// MoveReview represents the judgment of a single move.
+// Survival is the chance the move had not to hit a black hole; for a chord it's the chance of its riskiest cell.
+// SafeCell points to a cell that was proved safe when the move is a blunder.
+type MoveReview struct {
+	Move     game.Move
+	Verdict  Verdict
+	Survival float64
+	SafeCell board.Position
+}
+
+// Report represents the review of a game. Fatal is the index of the move that hit a black hole
+// or -1 if the game wasn't lost that way.
+type Report struct {
+	Moves []MoveReview
+	Fatal int
+}
+
+// Count returns the number of the moves with the verdict.
+func (r Report) Count(v Verdict) int {
+	n := 0
+
+	for _, m := range r.Moves {
+		if m.Verdict == v {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Game reviews the moves made in the game so far.
+func Game(g *game.Game) (Report, error) {
+	return Replay(replay.Record(g))
+}
+
+// Replay reviews the recorded moves. Only the cells the player could see are used for the judgments:
+// a cell is proved safe by the solver or by the absence of any arrangement of black holes that puts one there.
+// The review ends at a resignation, it isn't judged.
+func Replay(r replay.Replay) (Report, error) {
+	g, err := r.NewGame()
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to create a game: %w", err)
+	}
+
+	report := Report{Fatal: -1}
+
+	for i, m := range r.Moves {
+		if m.Kind == game.MoveResign {
+			break
+		}
+
+		mr := MoveReview{Move: m, Verdict: VerdictFlag, Survival: 1}
+
+		if m.Kind != game.MoveToggleFlag {
+			if mr, err = judge(g, m); err != nil {
+				return Report{}, fmt.Errorf("failed to review move #%d: %w", i+1, err)
+			}
+		}
+
+		if err := g.Apply(m); err != nil {
+			return Report{}, fmt.Errorf("%w: move #%d (%s at %v): %w", replay.ErrInvalidMove, i+1, m.Kind, m.Position, err)
+		}
+
+		report.Moves = append(report.Moves, mr)
+
+		if _, lost := g.LostAt(); lost {
+			report.Fatal = i
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// judge judges the move that opens cells before it's made on the game.
+func judge(g *game.Game, m game.Move) (MoveReview, error) {
+	state := g.BoardState()
+	mr := MoveReview{Move: m, Verdict: VerdictSafe, Survival: 1}
+
+	if g.Config().FirstClickSafe && !anyOpen(state) {
+		return mr, nil
+	}
+
+	result, err := probability.CalculateSteps(state, g.NumBlackHoles(), maxProbabilitySteps)
+	if err != nil {
+		return MoveReview{}, fmt.Errorf("failed to calculate probabilities: %w", err)
+	}
+
+	safe := safeCells(state, g.NumBlackHoles(), result)
+
+	var (
+		risk  float64
+		risky bool
+	)
+
+	for _, p := range openedBy(state, m) {
+		if !containsPosition(safe, p) {
+			risk = max(risk, result.Probabilities[p.Row][p.Col])
+			risky = true
+		}
+	}
+
+	if !risky {
+		return mr, nil
+	}
+
+	mr.Survival = 1 - risk
+	mr.Verdict = VerdictGuess
+
+	if len(safe) > 0 {
+		mr.Verdict = VerdictBlunder
+		mr.SafeCell = safe[0]
+	}
+
+	return mr, nil
+}
+
+// safeCells returns the hidden cells without flags that are proved to be free from black holes.
+func safeCells(state [][]board.CellValue, totalBlackHoles int, result probability.Result) []board.Position {
+	var safe []board.Position
+
+	for _, m := range solver.Solve(state, totalBlackHoles) {
+		if !m.BlackHole && state[m.Position.Row][m.Position.Col] == board.CellValueUnknown {
+			safe = append(safe, m.Position)
+		}
+	}
+
+	if !result.Exact {
+		return safe
+	}
+
+	for i, row := range state {
+		for j, v := range row {
+			pos := board.Position{Row: i, Col: j}
+
+			if v == board.CellValueUnknown && result.Probabilities[i][j] == 0 && !containsPosition(safe, pos) {
+				safe = append(safe, pos)
+			}
+		}
+	}
+
+	return safe
+}
+
+// openedBy returns the hidden cells the move opens itself, cascades of blank cells aren't included.
+func openedBy(state [][]board.CellValue, m game.Move) []board.Position {
+	if m.Kind == game.MoveOpen {
+		return []board.Position{m.Position}
+	}
+
+	var cells []board.Position
+
+	for _, p := range board.SurroundingPositions(len(state), len(state[0]), m.Position.Row, m.Position.Col) {
+		if state[p.Row][p.Col] == board.CellValueUnknown {
+			cells = append(cells, p)
+		}
+	}
+
+	return cells
+}
+
+func anyOpen(state [][]board.CellValue) bool {
+	for _, row := range state {
+		for _, v := range row {
+			if !v.IsHidden() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsPosition(positions []board.Position, pos board.Position) bool {
+	for _, p := range positions {
+		if p == pos {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package review_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/replay"
+	"proxx/internal/proxx/review"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the board is a single row: H 1 0 1 H
+var blackHoles = []board.Position{{Row: 0, Col: 0}, {Row: 0, Col: 4}}
+
+func move(kind game.MoveKind, col int) game.Move {
+	return game.Move{Kind: kind, Position: board.Position{Row: 0, Col: col}}
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name           string
+		firstClickSafe bool
+		moves          []game.Move
+		verdicts       []review.Verdict
+		survival       []float64
+		safeCell       board.Position
+		fatal          int
+	}{
+		{
+			name: "Blunder with a safe cell available",
+			// after the two clues the middle cell is the only one free from black holes
+			moves:    []game.Move{move(game.MoveOpen, 1), move(game.MoveOpen, 3), move(game.MoveOpen, 0)},
+			verdicts: []review.Verdict{review.VerdictGuess, review.VerdictGuess, review.VerdictBlunder},
+			survival: []float64{0.6, 0.5, 0},
+			safeCell: board.Position{Row: 0, Col: 2},
+			fatal:    2,
+		},
+		{
+			name: "Chord after a flag",
+			moves: []game.Move{move(game.MoveOpen, 1), move(game.MoveOpen, 3), move(game.MoveToggleFlag, 4),
+				move(game.MoveChord, 3)},
+			verdicts: []review.Verdict{review.VerdictGuess, review.VerdictGuess, review.VerdictFlag, review.VerdictSafe},
+			survival: []float64{0.6, 0.5, 1, 1},
+			fatal:    -1,
+		},
+		{
+			name:           "Safe first click",
+			firstClickSafe: true,
+			moves:          []game.Move{move(game.MoveOpen, 0)},
+			verdicts:       []review.Verdict{review.VerdictSafe},
+			survival:       []float64{1},
+			fatal:          -1,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := replay.Replay{Rows: 1, Cols: 5, NumBlackHoles: 2, FirstClickSafe: tc.firstClickSafe,
+				BlackHoles: blackHoles, Moves: tc.moves}
+
+			report, err := review.Replay(r)
+			require.NoError(t, err)
+
+			require.Len(t, report.Moves, len(tc.verdicts))
+
+			for i, m := range report.Moves {
+				assert.Equal(t, tc.moves[i], m.Move)
+				assert.Equal(t, tc.verdicts[i], m.Verdict, "move #%d", i+1)
+				assert.InDelta(t, tc.survival[i], m.Survival, 1e-9, "move #%d", i+1)
+
+				if m.Verdict == review.VerdictBlunder {
+					assert.Equal(t, tc.safeCell, m.SafeCell)
+				}
+			}
+
+			assert.Equal(t, tc.fatal, report.Fatal)
+		})
+	}
+}
+
+func TestReplay_InvalidMove(t *testing.T) {
+	t.Parallel()
+
+	r := replay.Replay{Rows: 1, Cols: 5, NumBlackHoles: 2, BlackHoles: blackHoles,
+		Moves: []game.Move{move(game.MoveOpen, 1), move(game.MoveOpen, 1)}}
+
+	_, err := review.Replay(r)
+	assert.ErrorIs(t, err, replay.ErrInvalidMove)
+}
+
+func TestGame(t *testing.T) {
+	t.Parallel()
+
+	g, err := game.NewGame(game.Config{NumRows: 1, NumCols: 5, NumBlackHoles: 2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator(blackHoles)})
+	require.NoError(t, err)
+
+	for _, col := range []int{1, 3, 0} {
+		_, err := g.OpenCell(0, col)
+		require.NoError(t, err)
+	}
+
+	report, err := review.Game(g)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Fatal)
+	assert.Equal(t, 2, report.Count(review.VerdictGuess))
+	assert.Equal(t, 1, report.Count(review.VerdictBlunder))
+}
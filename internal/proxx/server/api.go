@@ -0,0 +1,30 @@
+package server
+
+import (
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+)
+
+// moveRequest is the body of a request to open, flag or chord the cell, positions are zero-based.
+type moveRequest struct {
+	Row *int `json:"row"`
+	Col *int `json:"col"`
+}
+
+// gameView is what a client sees of a game. Revealed lists the cells opened by the last move.
+// The spectate ID is shown only to the player who creates the game, views sent to spectators have no IDs.
+type gameView struct {
+	ID         string `json:"id,omitempty"`
+	SpectateID string `json:"spectate_id,omitempty"`
+	api.Game
+	Revealed []api.Cell `json:"revealed,omitempty"`
+}
+
+func newGameView(id string, g *game.Game) gameView {
+	return gameView{ID: id, Game: api.NewGame(g)}
+}
+
+func (v gameView) withRevealed(r game.OpenResult) gameView {
+	v.Revealed = api.Revealed(r)
+	return v
+}
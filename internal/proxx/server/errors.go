@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/session"
+)
+
+var (
+	ErrInvalidRequest   = errors.New("invalid request")
+	ErrInvalidConfig    = errors.New("invalid game configuration")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	ErrNotFound         = errors.New("not found")
+)
+
+// errorCodes maps errors to HTTP statuses and the codes clients can rely on, the first match wins.
+var errorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{err: session.ErrSessionNotFound, status: http.StatusNotFound, code: "game_not_found"},
+	{err: ErrNotFound, status: http.StatusNotFound, code: "not_found"},
+	{err: ErrMethodNotAllowed, status: http.StatusMethodNotAllowed, code: "method_not_allowed"},
+	{err: ErrInvalidRequest, status: http.StatusBadRequest, code: "invalid_request"},
+	{err: api.ErrInvalidRequest, status: http.StatusBadRequest, code: "invalid_request"},
+	{err: game.ErrUnknownPreset, status: http.StatusBadRequest, code: "unknown_preset"},
+	{err: game.ErrUnknownLocator, status: http.StatusBadRequest, code: "unknown_locator"},
+	{err: ErrInvalidConfig, status: http.StatusBadRequest, code: "invalid_config"},
+	{err: api.ErrInvalidConfig, status: http.StatusBadRequest, code: "invalid_config"},
+	{err: game.ErrCellPositionIsOutsideBoard, status: http.StatusBadRequest, code: "position_outside_board"},
+	{err: game.ErrGameOver, status: http.StatusConflict, code: "game_over"},
+	{err: game.ErrCellAlreadyOpen, status: http.StatusConflict, code: "cell_already_open"},
+	{err: game.ErrCellFlagged, status: http.StatusConflict, code: "cell_flagged"},
+	{err: game.ErrCellNotOpen, status: http.StatusConflict, code: "cell_not_open"},
+	{err: game.ErrChordNotMatching, status: http.StatusConflict, code: "chord_not_matching"},
+}
+
+// errorResponse is the body of all the responses with errors.
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError writes the error as JSON with the HTTP status and the code it maps to.
+// Errors that don't map to anything are internal errors, their details aren't shown to clients.
+func writeError(w http.ResponseWriter, err error) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			writeJSON(w, c.status, errorResponse{Error: errorBody{Code: c.code, Message: err.Error()}})
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusInternalServerError,
+		errorResponse{Error: errorBody{Code: "internal", Message: "internal server error"}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/websocket"
+	"time"
+)
+
+const (
+	// queueSize is the number of messages queued for a connection. A connection that falls further behind
+	// is dropped, so a slow client never blocks the game.
+	queueSize    = 64
+	pingInterval = 30 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// messageSnapshot is the type of the message that holds the whole game, the other messages are typed by their events.
+const messageSnapshot = "snapshot"
+
+// message is pushed to the player and the spectators of a game. A snapshot holds the whole game,
+// the other messages hold the cells changed by a move. Cells are what the player sees, the same as in gameView.
+type message struct {
+	Type      string     `json:"type"`
+	Game      *gameView  `json:"game,omitempty"`
+	Cells     []api.Cell `json:"cells,omitempty"`
+	Exploded  *[2]int    `json:"exploded,omitempty"`
+	ElapsedMs int64      `json:"elapsed_ms"`
+}
+
+// subscriber queues the messages of a game for a connection. Messages are sent from the game's events,
+// while the session holds the game, and written to the connection by its own goroutine.
+type subscriber struct {
+	messages chan []byte
+	// dropped is closed when the queue overflows, no messages are sent after that.
+	dropped     chan struct{}
+	unsubscribe func()
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{messages: make(chan []byte, queueSize), dropped: make(chan struct{}), unsubscribe: func() {}}
+}
+
+// send queues the message without blocking. If the queue is full, the subscriber is dropped.
+func (s *subscriber) send(m message) {
+	select {
+	case <-s.dropped:
+		return
+	default:
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+
+	select {
+	case s.messages <- data:
+	default:
+		close(s.dropped)
+		s.unsubscribe()
+	}
+}
+
+// subscribe sends the snapshot of the game, then a message for every event the player can see.
+// It must be called while the session holds the game.
+func (s *subscriber) subscribe(g *game.Game) {
+	view := newGameView("", g)
+	s.send(message{Type: messageSnapshot, Game: &view, ElapsedMs: view.ElapsedMs})
+
+	s.unsubscribe = g.Subscribe(func(e game.Event) {
+		if m, ok := eventMessage(g, e); ok {
+			s.send(m)
+		}
+	})
+}
+
+// eventMessage returns the message for the event. An undone move changes the whole board, so a new snapshot is sent.
+func eventMessage(g *game.Game, e game.Event) (message, bool) {
+	ev, ok := api.NewEvent(g, e)
+	if !ok {
+		return message{}, false
+	}
+
+	m := message{Type: ev.Type, Cells: ev.Cells, Exploded: ev.Exploded, ElapsedMs: ev.ElapsedMs}
+
+	if ev.Type == api.EventMoveUndone {
+		m.Type = messageSnapshot
+		m.Game = &gameView{Game: *ev.Game}
+	}
+
+	return m, true
+}
+
+// watch streams the game to a WebSocket client until the client leaves, falls behind or the session ends.
+// The subscription is made before the upgrade, so an unknown game gets a regular error response.
+func (s *Server) watch(w http.ResponseWriter, r *http.Request, id string) {
+	done, err := s.cfg.Sessions.Done(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	sub := newSubscriber()
+
+	err = s.cfg.Sessions.Do(id, func(g *game.Game) error {
+		sub.subscribe(g)
+		return nil
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	defer func() {
+		_ = s.cfg.Sessions.Do(id, func(*game.Game) error {
+			sub.unsubscribe()
+			return nil
+		})
+	}()
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	stream(conn, sub, done)
+}
+
+// stream writes the queued messages to the connection and pings the client. The messages of clients are read
+// only to answer pings and notice the close, the game is played through the HTTP API.
+func stream(conn *websocket.Conn, sub *subscriber, done <-chan struct{}) {
+	left := make(chan struct{})
+
+	go func() {
+		defer close(left)
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	write := func(opcode int, data []byte) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return conn.WriteMessage(opcode, data) == nil
+	}
+
+	for {
+		select {
+		case data := <-sub.messages:
+			if !write(websocket.OpText, data) {
+				_ = conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-ticker.C:
+			if !write(websocket.OpPing, nil) {
+				_ = conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-sub.dropped:
+			_ = conn.Close(websocket.ClosePolicyViolation, "the client is too slow")
+			return
+		case <-done:
+			_ = conn.Close(websocket.CloseGoingAway, "the game has expired")
+			return
+		case <-left:
+			_ = conn.Close(websocket.CloseNormal, "")
+			return
+		}
+	}
+}
+
+// spectate streams the game of the spectator's ID. The ID of a game can't be derived from it,
+// so spectators can't make moves.
+func (s *Server) spectate(w http.ResponseWriter, r *http.Request, spectateID string) {
+	s.mu.Lock()
+	id, ok := s.spectators[spectateID]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, fmt.Errorf("%w: no game to spectate %q", ErrNotFound, spectateID))
+		return
+	}
+
+	s.watch(w, r, id)
+}
+
+// addSpectateID gives the game an ID for spectators, it's forgotten when the session ends.
+func (s *Server) addSpectateID(id string) (string, error) {
+	done, err := s.cfg.Sessions.Done(id)
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate an identifier: %w", err)
+	}
+
+	spectateID := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	s.spectators[spectateID] = id
+	s.mu.Unlock()
+
+	go func() {
+		<-done
+
+		s.mu.Lock()
+		delete(s.spectators, spectateID)
+		s.mu.Unlock()
+	}()
+
+	return spectateID, nil
+}
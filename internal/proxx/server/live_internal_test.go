@@ -0,0 +1,37 @@
+package server
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriber_SlowConsumer(t *testing.T) {
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 1,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	sub := newSubscriber()
+	sub.subscribe(g)
+
+	// nobody reads the messages, the moves never block
+	for i := 0; i < 2*queueSize; i++ {
+		require.NoError(t, g.ToggleFlag(0, 0))
+	}
+
+	select {
+	case <-sub.dropped:
+	default:
+		t.Fatal("the slow subscriber isn't dropped")
+	}
+
+	assert.Len(t, sub.messages, queueSize)
+
+	// nothing is queued after the subscriber is dropped
+	_, err = g.OpenCell(0, 1)
+	require.NoError(t, err)
+	assert.Len(t, sub.messages, queueSize)
+}
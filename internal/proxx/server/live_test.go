@@ -0,0 +1,153 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/server"
+	"proxx/internal/proxx/session"
+	"proxx/internal/proxx/websocket"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	Type  string    `json:"type"`
+	Game  *gameView `json:"game"`
+	Cells []struct {
+		Row   int             `json:"row"`
+		Col   int             `json:"col"`
+		Value board.CellValue `json:"value"`
+	} `json:"cells"`
+	Exploded *[2]int `json:"exploded"`
+}
+
+func (c *client) watch(path string) *websocket.Conn {
+	c.t.Helper()
+
+	conn, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(c.url, "http")+path)
+	require.NoError(c.t, err)
+
+	c.t.Cleanup(func() { conn.Close(websocket.CloseNormal, "") })
+
+	return conn
+}
+
+// readMessages reads the messages up to the one of the type, the raw messages are returned too.
+func readMessages(t *testing.T, conn *websocket.Conn, last string) ([]message, []string) {
+	t.Helper()
+
+	var (
+		messages []message
+		raw      []string
+	)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	for {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var m message
+		require.NoError(t, json.Unmarshal(data, &m))
+
+		messages = append(messages, m)
+		raw = append(raw, string(data))
+
+		if m.Type == last {
+			return messages, raw
+		}
+	}
+}
+
+func types(messages []message) []string {
+	var result []string
+
+	for _, m := range messages {
+		result = append(result, m.Type)
+	}
+
+	return result
+}
+
+func TestServer_Events(t *testing.T) {
+	c := newClient(t)
+	v := c.create()
+	require.NotEmpty(t, v.SpectateID)
+
+	player := c.watch("/api/games/" + v.ID + "/events")
+	spectator := c.watch("/api/spectate/" + v.SpectateID)
+
+	for _, conn := range []*websocket.Conn{player, spectator} {
+		snapshot, _ := readMessages(t, conn, "snapshot")
+		require.Len(t, snapshot, 1)
+		assert.Empty(t, snapshot[0].Game.ID)
+		assert.Empty(t, snapshot[0].Game.SpectateID)
+		assertNoLeak(t, *snapshot[0].Game)
+	}
+
+	hole, flagged := testLayout()[0], testLayout()[1]
+
+	status, _ := c.move(v.ID, "flag", flagged)
+	require.Equal(t, http.StatusOK, status)
+
+	status, _ = c.move(v.ID, "open", hole)
+	require.Equal(t, http.StatusOK, status)
+
+	playerMessages, _ := readMessages(t, player, "game_lost")
+	spectatorMessages, raw := readMessages(t, spectator, "game_lost")
+
+	assert.Equal(t, playerMessages, spectatorMessages)
+	assert.Equal(t, []string{"game_started", "cell_flagged", "cells_opened", "game_lost"}, types(spectatorMessages))
+
+	flag := spectatorMessages[1].Cells[0]
+	assert.Equal(t, flagged, board.Position{Row: flag.Row, Col: flag.Col})
+	assert.EqualValues(t, board.CellValueFlag, flag.Value)
+	assert.Equal(t, &[2]int{hole.Row, hole.Col}, spectatorMessages[3].Exploded)
+
+	for _, r := range raw {
+		assert.NotContains(t, r, v.ID, "the spectator sees the game's ID")
+	}
+
+	// a late joiner gets the game as it is now
+	late := c.watch("/api/spectate/" + v.SpectateID)
+
+	snapshot, _ := readMessages(t, late, "snapshot")
+	assert.Equal(t, game.StatusLost.String(), snapshot[0].Game.Status)
+	assert.Equal(t, &[2]int{hole.Row, hole.Col}, snapshot[0].Game.Exploded)
+	assert.EqualValues(t, board.CellValueBlackHole, snapshot[0].Game.Cells[hole.Row][hole.Col])
+}
+
+func TestServer_EventsOfExpiredGame(t *testing.T) {
+	sessions := session.NewManager(session.Config{TTL: time.Nanosecond})
+	ts := httptest.NewServer(server.New(server.Config{Sessions: sessions}))
+	t.Cleanup(ts.Close)
+
+	c := &client{t: t, url: ts.URL}
+	v := c.create()
+
+	conn := c.watch("/api/spectate/" + v.SpectateID)
+	readMessages(t, conn, "snapshot")
+
+	time.Sleep(time.Millisecond)
+	require.Equal(t, 1, sessions.Expire())
+
+	_, _, err := conn.ReadMessage()
+
+	var closeErr *websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, websocket.CloseGoingAway, closeErr.Code)
+
+	// the spectate ID is forgotten with the game
+	require.Eventually(t, func() bool {
+		var e errorResponse
+		return c.do(http.MethodGet, "/api/spectate/"+v.SpectateID, nil, &e) == http.StatusNotFound && e.Error.Code == "not_found"
+	}, 5*time.Second, 10*time.Millisecond)
+}
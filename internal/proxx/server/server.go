@@ -0,0 +1,291 @@
+// Package server hosts games over HTTP: a JSON API to create games, make moves and read their state,
+// and WebSocket streams of the games for players and spectators.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"proxx/internal/proxx/api"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/session"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxBodySize limits the size of a request body.
+	maxBodySize    = 1 << 16
+	apiPrefix      = "/api/games"
+	spectatePrefix = "/api/spectate/"
+	metricsPath    = "/api/metrics"
+	presetsPath    = "/api/presets"
+)
+
+// Config represents the configuration of a server.
+type Config struct {
+	// CustomPresets are available to clients in addition to the built-in presets.
+	CustomPresets []game.Preset
+	// MaxCells limits the number of cells on a board, the default is used if it's zero.
+	MaxCells int
+	// Sessions owns the games, a manager with the default configuration is used if it's nil.
+	Sessions *session.Manager
+}
+
+// Server serves the browser client from the root and the HTTP API:
+//
+//	POST /api/games              create a game, the body describes the board
+//	GET  /api/games/{id}         get the state of the game
+//	POST /api/games/{id}/open    open the cell, the body is {"row": 0, "col": 0}
+//	POST /api/games/{id}/flag    put a flag on the cell or remove it
+//	POST /api/games/{id}/chord   open the cells around the clue
+//	POST /api/games/{id}/resign  give the game up
+//	GET  /api/games/{id}/events  watch the game over WebSocket
+//	GET  /api/spectate/{sid}     watch the game over WebSocket by the spectate ID returned on creation
+//	GET  /api/presets            list the presets
+//	GET  /api/metrics            get the counters of the sessions
+//
+// Positions are zero-based. Responses hold what the player may see of the game, errors are
+// JSON objects {"error": {"code": "...", "message": "..."}}.
+//
+// A WebSocket client gets a snapshot of the game, then a message for every change. The player and
+// the spectators get the same messages, spectators can't make moves.
+type Server struct {
+	cfg Config
+	web http.Handler
+
+	mu sync.Mutex
+	// spectators maps the spectate IDs to the IDs of the games.
+	spectators map[string]string
+}
+
+// New creates a server without games.
+func New(cfg Config) *Server {
+	if cfg.MaxCells == 0 {
+		cfg.MaxCells = api.DefaultMaxCells
+	}
+
+	if cfg.Sessions == nil {
+		cfg.Sessions = session.NewManager(session.Config{})
+	}
+
+	return &Server{cfg: cfg, web: webHandler(), spectators: make(map[string]string)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeError(w, ErrMethodNotAllowed)
+			return
+		}
+
+		s.web.ServeHTTP(w, r)
+
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	if path == metricsPath || path == presetsPath {
+		if r.Method != http.MethodGet {
+			writeError(w, ErrMethodNotAllowed)
+			return
+		}
+
+		if path == presetsPath {
+			s.listPresets(w)
+		} else {
+			writeJSON(w, http.StatusOK, s.cfg.Sessions.Metrics())
+		}
+
+		return
+	}
+
+	if path == apiPrefix {
+		if r.Method != http.MethodPost {
+			writeError(w, ErrMethodNotAllowed)
+			return
+		}
+
+		s.createGame(w, r)
+
+		return
+	}
+
+	if spectateID, ok := strings.CutPrefix(path, spectatePrefix); ok {
+		if r.Method != http.MethodGet {
+			writeError(w, ErrMethodNotAllowed)
+			return
+		}
+
+		s.spectate(w, r, spectateID)
+
+		return
+	}
+
+	rest, ok := strings.CutPrefix(path, apiPrefix+"/")
+	if !ok {
+		writeError(w, fmt.Errorf("%w: %s", ErrNotFound, r.URL.Path))
+		return
+	}
+
+	id, action, _ := strings.Cut(rest, "/")
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.getGame(w, id)
+	case action == "":
+		writeError(w, ErrMethodNotAllowed)
+	case action == "events" && r.Method == http.MethodGet:
+		s.watch(w, r, id)
+	case action == "events":
+		writeError(w, ErrMethodNotAllowed)
+	case r.Method != http.MethodPost:
+		writeError(w, ErrMethodNotAllowed)
+	case action == "open" || action == "flag" || action == "chord":
+		s.move(w, r, id, action)
+	case action == "resign":
+		s.resign(w, id)
+	default:
+		writeError(w, fmt.Errorf("%w: %s", ErrNotFound, r.URL.Path))
+	}
+}
+
+func (s *Server) createGame(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateRequest
+
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	cfg, err := req.Config(s.cfg.CustomPresets, s.cfg.MaxCells)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	g, err := game.NewGame(cfg)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %w", ErrInvalidConfig, err))
+		return
+	}
+
+	// the view is taken before the game is shared
+	view := newGameView("", g)
+
+	id, err := s.cfg.Sessions.Create(g)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	view.ID = id
+
+	view.SpectateID, err = s.addSpectateID(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", apiPrefix+"/"+id)
+	writeJSON(w, http.StatusCreated, view)
+}
+
+func (s *Server) getGame(w http.ResponseWriter, id string) {
+	var view gameView
+
+	err := s.cfg.Sessions.Do(id, func(g *game.Game) error {
+		view = newGameView(id, g)
+		return nil
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) move(w http.ResponseWriter, r *http.Request, id string, action string) {
+	var req moveRequest
+
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if req.Row == nil || req.Col == nil {
+		writeError(w, fmt.Errorf("%w: row and col are required", ErrInvalidRequest))
+		return
+	}
+
+	var view gameView
+
+	err := s.cfg.Sessions.Do(id, func(g *game.Game) error {
+		var (
+			result game.OpenResult
+			err    error
+		)
+
+		switch action {
+		case "open":
+			result, err = g.OpenCell(*req.Row, *req.Col)
+		case "flag":
+			err = g.ToggleFlag(*req.Row, *req.Col)
+		case "chord":
+			result, err = g.Chord(*req.Row, *req.Col)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		view = newGameView(id, g)
+		if action != "flag" {
+			view = view.withRevealed(result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) resign(w http.ResponseWriter, id string) {
+	var view gameView
+
+	err := s.cfg.Sessions.Do(id, func(g *game.Game) error {
+		if err := g.Resign(); err != nil {
+			return err
+		}
+
+		view = newGameView(id, g)
+
+		return nil
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+// decodeBody decodes the JSON body of the request. Unknown fields are rejected, an empty body is fine.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	return nil
+}
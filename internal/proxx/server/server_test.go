@@ -0,0 +1,481 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/server"
+	"proxx/internal/proxx/session"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSeed = 1
+
+type gameView struct {
+	ID         string              `json:"id"`
+	SpectateID string              `json:"spectate_id"`
+	Rows       int                 `json:"rows"`
+	Cols       int                 `json:"cols"`
+	BlackHoles int                 `json:"black_holes"`
+	Flags      int                 `json:"flags"`
+	Status     string              `json:"status"`
+	Resigned   bool                `json:"resigned"`
+	Exploded   *[2]int             `json:"exploded"`
+	Cells      [][]board.CellValue `json:"cells"`
+	Revealed   []struct {
+		Row   int             `json:"row"`
+		Col   int             `json:"col"`
+		Value board.CellValue `json:"value"`
+	} `json:"revealed"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// client makes requests to the test server and decodes the responses.
+type client struct {
+	t   *testing.T
+	url string
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+
+	ts := httptest.NewServer(server.New(server.Config{}))
+	t.Cleanup(ts.Close)
+
+	return &client{t: t, url: ts.URL}
+}
+
+// do sends the request with the body encoded as JSON (a string is sent as is) and decodes the response into out.
+func (c *client) do(method string, path string, body any, out any) int {
+	c.t.Helper()
+
+	var payload []byte
+
+	switch b := body.(type) {
+	case nil:
+	case string:
+		payload = []byte(b)
+	default:
+		var err error
+		payload, err = json.Marshal(b)
+		require.NoError(c.t, err)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, bytes.NewReader(payload))
+	require.NoError(c.t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(c.t, "application/json", resp.Header.Get("Content-Type"))
+
+	if out != nil {
+		require.NoError(c.t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+// create creates a 4x4 game with 2 black holes placed by the test seed.
+func (c *client) create() gameView {
+	c.t.Helper()
+
+	var v gameView
+
+	status := c.do(http.MethodPost, "/api/games", map[string]any{"rows": 4, "cols": 4, "holes": 2, "seed": testSeed}, &v)
+	require.Equal(c.t, http.StatusCreated, status)
+
+	return v
+}
+
+func (c *client) move(id string, action string, pos board.Position) (int, gameView) {
+	c.t.Helper()
+
+	var v gameView
+	status := c.do(http.MethodPost, "/api/games/"+id+"/"+action, map[string]int{"row": pos.Row, "col": pos.Col}, &v)
+
+	return status, v
+}
+
+func (c *client) expectError(method string, path string, body any, status int, code string) {
+	c.t.Helper()
+
+	var e errorResponse
+
+	assert.Equal(c.t, status, c.do(method, path, body, &e))
+	assert.Equal(c.t, code, e.Error.Code)
+	assert.NotEmpty(c.t, e.Error.Message)
+}
+
+func testLayout() []board.Position {
+	return game.NewSeededUniformBlackHoleLocator(testSeed).LocateBlackHolesOnBoard(4, 4, 2)
+}
+
+func isBlackHole(pos board.Position) bool {
+	for _, p := range testLayout() {
+		if p == pos {
+			return true
+		}
+	}
+
+	return false
+}
+
+// assertNoLeak checks that the cells with black holes are closed in a game that isn't lost.
+func assertNoLeak(t *testing.T, v gameView) {
+	t.Helper()
+
+	for _, p := range testLayout() {
+		assert.True(t, v.Cells[p.Row][p.Col].IsHidden(), "black hole at %v is revealed", p)
+	}
+}
+
+func TestServer_Play(t *testing.T) {
+	t.Run("Win", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t)
+		v := c.create()
+
+		assert.NotEmpty(t, v.ID)
+		assert.Equal(t, 4, v.Rows)
+		assert.Equal(t, 2, v.BlackHoles)
+		assert.Equal(t, game.StatusInProgress.String(), v.Status)
+		assertNoLeak(t, v)
+
+		id := v.ID
+
+		for i := 0; i < 4 && v.Status == game.StatusInProgress.String(); i++ {
+			for j := 0; j < 4 && v.Status == game.StatusInProgress.String(); j++ {
+				pos := board.Position{Row: i, Col: j}
+				if isBlackHole(pos) || v.Cells[i][j] != board.CellValueUnknown {
+					continue
+				}
+
+				var status int
+
+				status, v = c.move(id, "open", pos)
+				require.Equal(t, http.StatusOK, status)
+				require.NotEmpty(t, v.Revealed)
+				assert.Equal(t, pos, board.Position{Row: v.Revealed[0].Row, Col: v.Revealed[0].Col})
+
+				if v.Status == game.StatusInProgress.String() {
+					assertNoLeak(t, v)
+				}
+			}
+		}
+
+		assert.Equal(t, game.StatusWon.String(), v.Status)
+
+		var got gameView
+		require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/games/"+id, nil, &got))
+		assert.Equal(t, v.Cells, got.Cells)
+	})
+
+	t.Run("Lose", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t)
+		id := c.create().ID
+		hole := testLayout()[0]
+
+		status, v := c.move(id, "flag", testLayout()[1])
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, v.Flags)
+		assertNoLeak(t, v)
+
+		status, v = c.move(id, "open", hole)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, game.StatusLost.String(), v.Status)
+		assert.Equal(t, &[2]int{hole.Row, hole.Col}, v.Exploded)
+		assert.EqualValues(t, board.CellValueBlackHole, v.Cells[hole.Row][hole.Col])
+
+		c.expectError(http.MethodPost, "/api/games/"+id+"/open", map[string]int{"row": 0, "col": 0},
+			http.StatusConflict, "game_over")
+	})
+
+	t.Run("Resign", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t)
+		id := c.create().ID
+
+		var v gameView
+		require.Equal(t, http.StatusOK, c.do(http.MethodPost, "/api/games/"+id+"/resign", nil, &v))
+		assert.Equal(t, game.StatusLost.String(), v.Status)
+		assert.True(t, v.Resigned)
+		assert.Nil(t, v.Exploded)
+
+		for _, p := range testLayout() {
+			assert.EqualValues(t, board.CellValueBlackHole, v.Cells[p.Row][p.Col])
+		}
+
+		c.expectError(http.MethodPost, "/api/games/"+id+"/resign", nil, http.StatusConflict, "game_over")
+	})
+
+	t.Run("Chord", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t)
+		id := c.create().ID
+
+		c.expectError(http.MethodPost, "/api/games/"+id+"/chord", map[string]int{"row": 0, "col": 0},
+			http.StatusConflict, "cell_not_open")
+	})
+
+	t.Run("Games are independent", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t)
+		first, second := c.create(), c.create()
+		require.NotEqual(t, first.ID, second.ID)
+
+		status, _ := c.move(first.ID, "flag", board.Position{Row: 0, Col: 0})
+		require.Equal(t, http.StatusOK, status)
+
+		var v gameView
+		require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/games/"+second.ID, nil, &v))
+		assert.Equal(t, 0, v.Flags)
+	})
+}
+
+func TestServer_Errors(t *testing.T) {
+	c := newClient(t)
+	id := c.create().ID
+
+	safe := board.Position{}
+	for isBlackHole(safe) {
+		safe.Col++
+	}
+
+	status, _ := c.move(id, "open", safe)
+	require.Equal(t, http.StatusOK, status)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+	}{
+		{name: "Unknown game", method: http.MethodGet, path: "/api/games/nope",
+			status: http.StatusNotFound, code: "game_not_found"},
+		{name: "Move in an unknown game", method: http.MethodPost, path: "/api/games/nope/open",
+			body: map[string]int{"row": 0, "col": 0}, status: http.StatusNotFound, code: "game_not_found"},
+		{name: "Events of an unknown game", method: http.MethodGet, path: "/api/games/nope/events",
+			status: http.StatusNotFound, code: "game_not_found"},
+		{name: "Wrong method of events", method: http.MethodPost, path: "/api/games/" + id + "/events",
+			status: http.StatusMethodNotAllowed, code: "method_not_allowed"},
+		{name: "Unknown spectate ID", method: http.MethodGet, path: "/api/spectate/" + id,
+			status: http.StatusNotFound, code: "not_found"},
+		{name: "Unknown action", method: http.MethodPost, path: "/api/games/" + id + "/explode",
+			status: http.StatusNotFound, code: "not_found"},
+		{name: "Unknown path", method: http.MethodGet, path: "/api/players",
+			status: http.StatusNotFound, code: "not_found"},
+		{name: "Wrong method", method: http.MethodGet, path: "/api/games",
+			status: http.StatusMethodNotAllowed, code: "method_not_allowed"},
+		{name: "Wrong method of a move", method: http.MethodGet, path: "/api/games/" + id + "/open",
+			status: http.StatusMethodNotAllowed, code: "method_not_allowed"},
+		{name: "Malformed JSON", method: http.MethodPost, path: "/api/games", body: "{rows",
+			status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "Unknown field", method: http.MethodPost, path: "/api/games", body: `{"lives": 3}`,
+			status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "Invalid configuration", method: http.MethodPost, path: "/api/games",
+			body:   map[string]int{"rows": 2, "cols": 2, "holes": 4},
+			status: http.StatusBadRequest, code: "invalid_config"},
+		{name: "Board is too large", method: http.MethodPost, path: "/api/games",
+			body:   map[string]int{"rows": 1000, "cols": 1000, "holes": 4},
+			status: http.StatusBadRequest, code: "invalid_config"},
+		{name: "Unknown preset", method: http.MethodPost, path: "/api/games", body: map[string]string{"preset": "hard"},
+			status: http.StatusBadRequest, code: "unknown_preset"},
+		{name: "Unknown locator", method: http.MethodPost, path: "/api/games",
+			body:   map[string]any{"preset": "beginner", "locator": "magic"},
+			status: http.StatusBadRequest, code: "unknown_locator"},
+		{name: "Position is missing", method: http.MethodPost, path: "/api/games/" + id + "/open",
+			body: map[string]int{"row": 0}, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "Position outside the board", method: http.MethodPost, path: "/api/games/" + id + "/open",
+			body: map[string]int{"row": 4, "col": 0}, status: http.StatusBadRequest, code: "position_outside_board"},
+		{name: "Cell is already open", method: http.MethodPost, path: "/api/games/" + id + "/open",
+			body:   map[string]int{"row": safe.Row, "col": safe.Col},
+			status: http.StatusConflict, code: "cell_already_open"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := &client{t: t, url: c.url}
+			c.expectError(tc.method, tc.path, tc.body, tc.status, tc.code)
+		})
+	}
+}
+
+func TestServer_Preset(t *testing.T) {
+	c := newClient(t)
+
+	var v gameView
+	require.Equal(t, http.StatusCreated, c.do(http.MethodPost, "/api/games", `{"preset": "beginner"}`, &v))
+	assert.Equal(t, 9, v.Rows)
+	assert.Equal(t, 10, v.BlackHoles)
+
+	for _, row := range v.Cells {
+		assert.Equal(t, strings.Repeat("?", 9), joinCells(row))
+	}
+}
+
+func TestServer_Presets(t *testing.T) {
+	ts := httptest.NewServer(server.New(server.Config{
+		CustomPresets: []game.Preset{{Name: "dense", NumRows: 10, NumCols: 10, BlackHoleDensity: 25}},
+	}))
+	t.Cleanup(ts.Close)
+
+	c := &client{t: t, url: ts.URL}
+
+	var presets []struct {
+		Name  string `json:"name"`
+		Rows  int    `json:"rows"`
+		Cols  int    `json:"cols"`
+		Holes int    `json:"holes"`
+	}
+
+	require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/presets", nil, &presets))
+	require.Len(t, presets, 4)
+
+	assert.Equal(t, "beginner", presets[0].Name)
+	assert.Equal(t, []int{9, 9, 10}, []int{presets[0].Rows, presets[0].Cols, presets[0].Holes})
+	assert.Equal(t, "dense", presets[3].Name)
+	assert.Equal(t, 25, presets[3].Holes)
+}
+
+func joinCells(row []board.CellValue) string {
+	var b strings.Builder
+
+	for _, v := range row {
+		b.WriteString(string(v))
+	}
+
+	return b.String()
+}
+
+func TestServer_Web(t *testing.T) {
+	c := newClient(t)
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(c.url + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(body)
+	}
+
+	resp, page := get("/")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, page, `<script src="app.js">`)
+
+	for _, path := range []string{"/app.js", "/style.css"} {
+		resp, body := get(path)
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+		assert.Contains(t, page, strings.TrimPrefix(path, "/"))
+
+		// the client works without access to other hosts
+		assert.NotContains(t, body, "://", path)
+	}
+
+	assert.NotContains(t, page, "://")
+
+	resp, _ = get("/favicon.ico")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	c.expectError(http.MethodPost, "/", nil, http.StatusMethodNotAllowed, "method_not_allowed")
+}
+
+func TestServer_ConcurrentClients(t *testing.T) {
+	c := newClient(t)
+	shared := c.create().ID
+
+	const clients = 20
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < clients; i++ {
+		wg.Add(2)
+
+		// every client flags the same cell of the shared game and reads it
+		go func() {
+			defer wg.Done()
+
+			c := &client{t: t, url: c.url}
+
+			status, _ := c.move(shared, "flag", board.Position{Row: 0, Col: 0})
+			assert.Equal(t, http.StatusOK, status)
+
+			var v gameView
+			assert.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/games/"+shared, nil, &v))
+		}()
+
+		// and plays a game of its own
+		go func() {
+			defer wg.Done()
+
+			c := &client{t: t, url: c.url}
+			id := c.create().ID
+
+			for _, p := range testLayout() {
+				status, _ := c.move(id, "flag", p)
+				assert.Equal(t, http.StatusOK, status)
+			}
+
+			var v gameView
+			assert.Equal(t, http.StatusOK, c.do(http.MethodPost, "/api/games/"+id+"/resign", nil, &v))
+			assert.Equal(t, game.StatusLost.String(), v.Status)
+		}()
+	}
+
+	wg.Wait()
+
+	// an even number of toggles leaves the cell without a flag
+	var v gameView
+	require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/games/"+shared, nil, &v))
+	assert.Equal(t, 0, v.Flags)
+
+	var metrics session.Metrics
+	require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/api/metrics", nil, &metrics))
+	assert.Equal(t, clients+1, metrics.Active)
+	assert.Equal(t, clients+1, metrics.Created)
+	assert.Equal(t, 0, metrics.Busy)
+}
+
+func TestServer_ExpiredGame(t *testing.T) {
+	sessions := session.NewManager(session.Config{TTL: time.Nanosecond})
+	ts := httptest.NewServer(server.New(server.Config{Sessions: sessions}))
+	t.Cleanup(ts.Close)
+
+	c := &client{t: t, url: ts.URL}
+	id := c.create().ID
+
+	time.Sleep(time.Millisecond)
+	require.Equal(t, 1, sessions.Expire())
+
+	c.expectError(http.MethodGet, "/api/games/"+id, nil, http.StatusNotFound, "game_not_found")
+}
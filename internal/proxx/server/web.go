@@ -0,0 +1,44 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"proxx/internal/proxx/game"
+)
+
+// webFiles is the browser client. It's self-contained: nothing is loaded from other hosts.
+//
+//go:embed web
+var webFiles embed.FS
+
+// webHandler serves the browser client from the root of the server.
+func webHandler() http.Handler {
+	files, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+
+	return http.FileServer(http.FS(files))
+}
+
+// presetView describes a preset to clients, the number of black holes is computed from the density if needed.
+type presetView struct {
+	Name  string `json:"name"`
+	Rows  int    `json:"rows"`
+	Cols  int    `json:"cols"`
+	Holes int    `json:"holes"`
+}
+
+func (s *Server) listPresets(w http.ResponseWriter) {
+	presets := game.Presets(s.cfg.CustomPresets...)
+	views := make([]presetView, 0, len(presets))
+
+	for _, p := range presets {
+		cfg := p.Config(nil)
+
+		views = append(views, presetView{Name: p.Name, Rows: cfg.NumRows, Cols: cfg.NumCols, Holes: cfg.BlackHoleCount()})
+	}
+
+	writeJSON(w, http.StatusOK, views)
+}
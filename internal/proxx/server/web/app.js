@@ -0,0 +1,339 @@
+// The browser client of proxx. The player makes moves through the JSON API and the board is drawn from
+// the responses. A page opened with ?spectate=<id> watches the game over WebSocket instead.
+"use strict";
+
+const boardEl = document.getElementById("board");
+const presetEl = document.getElementById("preset");
+const firstClickSafeEl = document.getElementById("first-click-safe");
+const newGameEl = document.getElementById("new-game");
+const resignEl = document.getElementById("resign");
+const holesEl = document.getElementById("holes");
+const statusEl = document.getElementById("status");
+const timerEl = document.getElementById("timer");
+const messageEl = document.getElementById("message");
+const shareEl = document.getElementById("share");
+const shareLinkEl = document.getElementById("share-link");
+
+const UNKNOWN = "?";
+const FLAG = "F";
+const BLACK_HOLE = "H";
+const BLANK = "0";
+
+// game is the last view of the game, the same object the API returns.
+let game = null;
+let gameId = null;
+let busy = false;
+
+// clock shows the time of the server: the elapsed time it reported and when it was received.
+const clock = { elapsed: 0, since: 0, running: false };
+
+async function api(method, path, body) {
+  const options = { method };
+
+  if (body !== undefined) {
+    options.headers = { "Content-Type": "application/json" };
+    options.body = JSON.stringify(body);
+  }
+
+  const resp = await fetch(path, options);
+  const data = await resp.json();
+
+  if (!resp.ok) {
+    throw new Error(data.error ? data.error.message : resp.statusText);
+  }
+
+  return data;
+}
+
+function showMessage(text) {
+  messageEl.textContent = text;
+}
+
+function setClock(elapsed, running) {
+  clock.elapsed = elapsed;
+  clock.since = performance.now();
+  clock.running = running;
+  tick();
+}
+
+function tick() {
+  const elapsed = clock.elapsed + (clock.running ? performance.now() - clock.since : 0);
+  timerEl.textContent = Math.floor(elapsed / 1000);
+}
+
+// started tells whether the clock of the game runs: it starts with the first move.
+function started(view) {
+  return view.elapsed_ms > 0 || view.cells.some((row) => row.some((c) => c !== UNKNOWN));
+}
+
+function isOver(view) {
+  return view.status !== "in progress";
+}
+
+// cellKind returns the class of the cell, the same kinds as in the HTML boards of the CLI.
+function cellKind(view, row, col, value) {
+  const exploded = view.exploded && view.exploded[0] === row && view.exploded[1] === col;
+
+  switch (value) {
+    case UNKNOWN:
+      return "hidden";
+    case FLAG:
+      return view.status === "lost" ? "wrong-flag" : "flag";
+    case BLACK_HOLE:
+      return exploded ? "exploded" : "black-hole";
+    case BLANK:
+      return "blank";
+    default:
+      return `clue clue-${value}`;
+  }
+}
+
+function cellText(value) {
+  switch (value) {
+    case UNKNOWN:
+    case BLANK:
+      return "";
+    case FLAG:
+      return "⚑";
+    case BLACK_HOLE:
+      return "●";
+    default:
+      return value;
+  }
+}
+
+// render draws the game, the buttons are created again only when the size of the board changes.
+function render(view) {
+  if (boardEl.childElementCount !== view.rows * view.cols) {
+    boardEl.replaceChildren();
+    boardEl.style.gridTemplateColumns = `repeat(${view.cols}, auto)`;
+
+    for (let row = 0; row < view.rows; row++) {
+      for (let col = 0; col < view.cols; col++) {
+        const button = document.createElement("button");
+        button.type = "button";
+        button.dataset.row = row;
+        button.dataset.col = col;
+        boardEl.append(button);
+      }
+    }
+  }
+
+  let flags = 0;
+
+  for (const button of boardEl.children) {
+    const row = Number(button.dataset.row);
+    const col = Number(button.dataset.col);
+    const value = view.cells[row][col];
+
+    if (value === FLAG) {
+      flags++;
+    }
+
+    button.className = cellKind(view, row, col, value);
+    button.textContent = cellText(value);
+    button.title = `row ${row}, column ${col}`;
+  }
+
+  holesEl.textContent = view.black_holes - flags;
+
+  switch (view.status) {
+    case "won":
+      statusEl.textContent = "You won!";
+      break;
+    case "lost":
+      statusEl.textContent = view.resigned ? "Given up" : "A black hole!";
+      break;
+    default:
+      statusEl.textContent = "";
+  }
+
+  resignEl.disabled = gameId === null || isOver(view);
+}
+
+function show(view) {
+  game = view;
+  setClock(view.elapsed_ms, started(view) && !isOver(view));
+  render(view);
+}
+
+async function newGame(event) {
+  event.preventDefault();
+  showMessage("");
+
+  try {
+    const view = await api("POST", "/api/games", {
+      preset: presetEl.value,
+      first_click_safe: firstClickSafeEl.checked,
+    });
+
+    gameId = view.id;
+
+    const url = new URL(location.href);
+    url.search = new URLSearchParams({ spectate: view.spectate_id }).toString();
+    shareLinkEl.href = url.toString();
+    shareLinkEl.textContent = url.toString();
+    shareEl.hidden = false;
+
+    show(view);
+  } catch (err) {
+    showMessage(err.message);
+  }
+}
+
+async function move(action, row, col) {
+  if (gameId === null || busy || isOver(game)) {
+    return;
+  }
+
+  busy = true;
+  showMessage("");
+
+  try {
+    show(await api("POST", `/api/games/${gameId}/${action}`, { row, col }));
+  } catch (err) {
+    showMessage(err.message);
+  } finally {
+    busy = false;
+  }
+}
+
+async function resign() {
+  if (gameId === null || isOver(game)) {
+    return;
+  }
+
+  try {
+    show(await api("POST", `/api/games/${gameId}/resign`));
+  } catch (err) {
+    showMessage(err.message);
+  }
+}
+
+function cellOf(event) {
+  const button = event.target.closest("button");
+  if (!button || !boardEl.contains(button) || game === null) {
+    return null;
+  }
+
+  const row = Number(button.dataset.row);
+  const col = Number(button.dataset.col);
+
+  return { row, col, value: game.cells[row][col] };
+}
+
+function isClue(value) {
+  return value >= "1" && value <= "8";
+}
+
+function onClick(event) {
+  const cell = cellOf(event);
+  if (cell === null) {
+    return;
+  }
+
+  if (cell.value === UNKNOWN) {
+    move("open", cell.row, cell.col);
+  } else if (isClue(cell.value)) {
+    move("chord", cell.row, cell.col);
+  }
+}
+
+function onContextMenu(event) {
+  event.preventDefault();
+
+  const cell = cellOf(event);
+  if (cell !== null && (cell.value === UNKNOWN || cell.value === FLAG)) {
+    move("flag", cell.row, cell.col);
+  }
+}
+
+function onAuxClick(event) {
+  const cell = cellOf(event);
+  if (event.button === 1 && cell !== null && isClue(cell.value)) {
+    move("chord", cell.row, cell.col);
+  }
+}
+
+// play sets up the page for a player: presets are loaded and a new game is started.
+async function play() {
+  try {
+    for (const preset of await api("GET", "/api/presets")) {
+      const option = document.createElement("option");
+      option.value = preset.name;
+      option.textContent = `${preset.name} (${preset.rows}×${preset.cols}, ${preset.holes} black holes)`;
+      presetEl.append(option);
+    }
+  } catch (err) {
+    showMessage(err.message);
+    return;
+  }
+
+  boardEl.addEventListener("click", onClick);
+  boardEl.addEventListener("contextmenu", onContextMenu);
+  boardEl.addEventListener("auxclick", onAuxClick);
+  newGameEl.addEventListener("submit", newGame);
+  resignEl.addEventListener("click", resign);
+
+  newGameEl.requestSubmit();
+}
+
+// applyMessage updates the game with a message of the server's stream.
+function applyMessage(m) {
+  if (m.type === "snapshot") {
+    show(m.game);
+    return;
+  }
+
+  if (game === null) {
+    return;
+  }
+
+  for (const c of m.cells || []) {
+    game.cells[c.row][c.col] = c.value;
+  }
+
+  switch (m.type) {
+    case "game_won":
+      game.status = "won";
+      break;
+    case "game_lost":
+      game.status = "lost";
+      game.exploded = m.exploded;
+      break;
+    case "game_resigned":
+      game.status = "lost";
+      game.resigned = true;
+      break;
+  }
+
+  game.elapsed_ms = m.elapsed_ms;
+  setClock(m.elapsed_ms, (m.type === "game_started" || started(game)) && !isOver(game));
+  render(game);
+}
+
+// spectate sets up the page to watch the game, the board can't be clicked.
+function spectate(spectateId) {
+  newGameEl.hidden = true;
+  boardEl.addEventListener("contextmenu", (event) => event.preventDefault());
+
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const ws = new WebSocket(`${scheme}//${location.host}/api/spectate/${encodeURIComponent(spectateId)}`);
+
+  ws.onopen = () => showMessage("Watching the game.");
+  ws.onmessage = (event) => applyMessage(JSON.parse(event.data));
+  ws.onclose = (event) => {
+    clock.running = false;
+    showMessage(event.reason ? `The stream has ended: ${event.reason}.` : "The stream has ended.");
+  };
+}
+
+setInterval(tick, 250);
+
+const spectateId = new URLSearchParams(location.search).get("spectate");
+
+if (spectateId) {
+  spectate(spectateId);
+} else {
+  play();
+}
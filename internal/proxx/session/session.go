@@ -0,0 +1,213 @@
+// Package session owns games shared between goroutines: actions on a game are serialized,
+// different games are played in parallel and idle games expire.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/game"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the time an idle session lives if the manager's TTL isn't configured.
+const DefaultTTL = 30 * time.Minute
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Config represents the configuration of a manager.
+type Config struct {
+	// TTL is the time after the last action when a session expires, DefaultTTL is used if it's zero.
+	TTL time.Duration
+	// Now returns the current time, time.Now is used if it's nil.
+	Now func() time.Time
+}
+
+// Metrics represents the counters of a manager.
+type Metrics struct {
+	// Active is the number of live sessions, Busy is the number of them running an action right now.
+	Active  int `json:"active"`
+	Busy    int `json:"busy"`
+	Created int `json:"created"`
+	Expired int `json:"expired"`
+	Removed int `json:"removed"`
+	Actions int `json:"actions"`
+}
+
+// session holds a game. mu serializes the actions on the game,
+// lastUsed and busy are guarded by the manager's mutex. done is closed when the session ends.
+type session struct {
+	mu   sync.Mutex
+	game *game.Game
+	done chan struct{}
+
+	lastUsed time.Time
+	// busy is the number of actions running or waiting for the game, a busy session doesn't expire.
+	busy int
+}
+
+// Manager owns games identified by random IDs. It's safe for concurrent use.
+type Manager struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	metrics  Metrics
+}
+
+// NewManager creates a manager without sessions.
+func NewManager(cfg Config) *Manager {
+	if cfg.TTL == 0 {
+		cfg.TTL = DefaultTTL
+	}
+
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Manager{ttl: cfg.TTL, now: cfg.Now, sessions: make(map[string]*session)}
+}
+
+// Create starts a session with the game and returns its ID. The game mustn't be used directly after that.
+func (m *Manager) Create(g *game.Game) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[id] = &session{game: g, done: make(chan struct{}), lastUsed: m.now()}
+	m.metrics.Created++
+
+	return id, nil
+}
+
+// Do runs fn on the game of the session. Calls for the same session run one at a time in the order they
+// get the game, calls for different sessions run in parallel. fn mustn't keep the game after it returns.
+// Returns ErrSessionNotFound if there is no such session or it has expired, otherwise the error of fn.
+func (m *Manager) Do(id string, fn func(g *game.Game) error) error {
+	m.mu.Lock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+
+	s.busy++
+	m.metrics.Actions++
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		s.busy--
+		s.lastUsed = m.now()
+		m.mu.Unlock()
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s.game)
+}
+
+// Done returns a channel that's closed when the session is removed or expires.
+func (m *Manager) Done(id string) (<-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+
+	return s.done, nil
+}
+
+// Remove ends the session. Actions already running or waiting for the game complete.
+func (m *Manager) Remove(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrSessionNotFound, id)
+	}
+
+	delete(m.sessions, id)
+	close(s.done)
+	m.metrics.Removed++
+
+	return nil
+}
+
+// Expire removes the sessions idle for longer than the TTL and returns their number.
+// Sessions with actions running or waiting never expire.
+func (m *Manager) Expire() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	expired := 0
+
+	for id, s := range m.sessions {
+		if s.busy == 0 && now.Sub(s.lastUsed) > m.ttl {
+			delete(m.sessions, id)
+			close(s.done)
+			expired++
+		}
+	}
+
+	m.metrics.Expired += expired
+
+	return expired
+}
+
+// Run expires idle sessions every interval until the context is done.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Expire()
+		}
+	}
+}
+
+// Metrics returns the current counters.
+func (m *Manager) Metrics() Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics := m.metrics
+	metrics.Active = len(m.sessions)
+
+	for _, s := range m.sessions {
+		if s.busy > 0 {
+			metrics.Busy++
+		}
+	}
+
+	return metrics
+}
+
+// newID returns a random identifier of a session, it can't be guessed by other clients.
+func newID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate an identifier: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,232 @@
+package session_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/session"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGame(t *testing.T) *game.Game {
+	t.Helper()
+
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 2,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	return g
+}
+
+// fakeClock is a clock moved by the test.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestManager_Do(t *testing.T) {
+	t.Run("Actions on the same game are serialized", func(t *testing.T) {
+		t.Parallel()
+
+		m := session.NewManager(session.Config{})
+
+		id, err := m.Create(newGame(t))
+		require.NoError(t, err)
+
+		const clients = 50
+
+		// the race detector reports the counter if two actions run at the same time
+		counter := 0
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < clients; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				err := m.Do(id, func(g *game.Game) error {
+					counter++
+					return g.ToggleFlag(0, 0)
+				})
+				assert.NoError(t, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, clients, counter)
+
+		// an even number of toggles leaves the cell without a flag
+		require.NoError(t, m.Do(id, func(g *game.Game) error {
+			assert.EqualValues(t, board.CellValueUnknown, g.BoardState()[0][0])
+			assert.Len(t, g.History(), clients)
+			return nil
+		}))
+	})
+
+	t.Run("Different games run in parallel", func(t *testing.T) {
+		t.Parallel()
+
+		m := session.NewManager(session.Config{})
+
+		first, err := m.Create(newGame(t))
+		require.NoError(t, err)
+
+		second, err := m.Create(newGame(t))
+		require.NoError(t, err)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error, 1)
+
+		go func() {
+			done <- m.Do(first, func(*game.Game) error {
+				close(entered)
+				<-release
+				return nil
+			})
+		}()
+
+		<-entered
+
+		// the first game is busy, the second one is still available
+		finished := make(chan struct{})
+
+		go func() {
+			assert.NoError(t, m.Do(second, func(*game.Game) error { return nil }))
+			close(finished)
+		}()
+
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatal("an action on another game is blocked")
+		}
+
+		assert.Equal(t, 1, m.Metrics().Busy)
+
+		close(release)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("Unknown session", func(t *testing.T) {
+		t.Parallel()
+
+		m := session.NewManager(session.Config{})
+
+		err := m.Do("nope", func(*game.Game) error { return nil })
+		assert.ErrorIs(t, err, session.ErrSessionNotFound)
+	})
+
+	t.Run("Error of the action", func(t *testing.T) {
+		t.Parallel()
+
+		m := session.NewManager(session.Config{})
+
+		id, err := m.Create(newGame(t))
+		require.NoError(t, err)
+
+		err = m.Do(id, func(g *game.Game) error {
+			_, err := g.Chord(0, 0)
+			return err
+		})
+		assert.ErrorIs(t, err, game.ErrCellNotOpen)
+	})
+}
+
+func TestManager_Expire(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := session.NewManager(session.Config{TTL: time.Minute, Now: clock.Now})
+
+	idle, err := m.Create(newGame(t))
+	require.NoError(t, err)
+
+	idleDone, err := m.Done(idle)
+	require.NoError(t, err)
+
+	active, err := m.Create(newGame(t))
+	require.NoError(t, err)
+
+	clock.Advance(50 * time.Second)
+	require.NoError(t, m.Do(active, func(*game.Game) error { return nil }))
+
+	clock.Advance(20 * time.Second)
+	assert.Equal(t, 1, m.Expire())
+
+	assert.ErrorIs(t, m.Do(idle, func(*game.Game) error { return nil }), session.ErrSessionNotFound)
+	assert.True(t, isClosed(idleDone))
+
+	_, err = m.Done(idle)
+	assert.ErrorIs(t, err, session.ErrSessionNotFound)
+	assert.NoError(t, m.Do(active, func(*game.Game) error { return nil }))
+
+	// a session doesn't expire while an action runs
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- m.Do(active, func(*game.Game) error {
+			close(entered)
+			<-release
+			return nil
+		})
+	}()
+
+	<-entered
+	clock.Advance(time.Hour)
+	assert.Equal(t, 0, m.Expire())
+
+	close(release)
+	require.NoError(t, <-done)
+
+	activeDone, err := m.Done(active)
+	require.NoError(t, err)
+	assert.False(t, isClosed(activeDone))
+
+	require.NoError(t, m.Remove(active))
+	assert.True(t, isClosed(activeDone))
+	assert.ErrorIs(t, m.Remove(active), session.ErrSessionNotFound)
+
+	assert.Equal(t, session.Metrics{Created: 2, Expired: 1, Removed: 1, Actions: 3}, m.Metrics())
+}
+
+func TestManager_Metrics(t *testing.T) {
+	m := session.NewManager(session.Config{})
+
+	for i := 0; i < 3; i++ {
+		_, err := m.Create(newGame(t))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, session.Metrics{Active: 3, Created: 3}, m.Metrics())
+}
+
+func isClosed(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,127 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/probability"
+	"proxx/internal/proxx/solver"
+)
+
+// maxProbabilitySteps limits the enumeration of black hole arrangements for a guess.
+// A limit in steps rather than in time keeps the games the same on every machine.
+const maxProbabilitySteps = 1 << 20
+
+// GameResult represents a game played by the bot.
+// Guesses counts the moves that weren't proved safe, the first move isn't counted since nothing is known then.
+// Opened is the number of cells free from black holes the bot opened out of Free.
+type GameResult struct {
+	Seed    int64
+	Won     bool
+	Moves   int
+	Guesses int
+	Opened  int
+	Free    int
+}
+
+// Progress returns the share of the free cells opened, from 0 to 1.
+func (r GameResult) Progress() float64 {
+	if r.Free == 0 {
+		return 0
+	}
+
+	return float64(r.Opened) / float64(r.Free)
+}
+
+// Play plays the game to the end with the solver: every cell that can be proved safe is opened,
+// when there are none the cell least likely to hold a black hole is opened. The bot never flags cells.
+// The same game is always played the same way.
+func Play(g *game.Game) (GameResult, error) {
+	var result GameResult
+
+	for !g.IsOver() {
+		report, err := solver.Play[game.OpenResult](g)
+		if err != nil {
+			return result, err
+		}
+
+		for _, m := range report.Moves {
+			if !m.BlackHole {
+				result.Moves++
+			}
+		}
+
+		if !report.Stuck {
+			break
+		}
+
+		pos, err := safestCell(g.BoardState(), g.NumBlackHoles())
+		if err != nil {
+			return result, err
+		}
+
+		if result.Moves > 0 {
+			result.Guesses++
+		}
+
+		result.Moves++
+
+		if _, err := g.OpenCell(pos.Row, pos.Col); err != nil {
+			return result, fmt.Errorf("failed to open the cell at %v: %w", pos, err)
+		}
+	}
+
+	result.Won = g.IsWon()
+	result.Opened, result.Free = countOpened(g)
+
+	return result, nil
+}
+
+// safestCell returns the hidden cell with the lowest probability of a black hole,
+// the first one in the reading order if there are several.
+func safestCell(state [][]board.CellValue, totalBlackHoles int) (board.Position, error) {
+	result, err := probability.CalculateSteps(state, totalBlackHoles, maxProbabilitySteps)
+	if err != nil {
+		return board.Position{}, fmt.Errorf("failed to calculate probabilities: %w", err)
+	}
+
+	var (
+		best   board.Position
+		lowest float64
+		found  bool
+	)
+
+	for i, row := range state {
+		for j, v := range row {
+			if v != board.CellValueUnknown {
+				continue
+			}
+
+			if p := result.Probabilities[i][j]; !found || p < lowest {
+				best, lowest, found = board.Position{Row: i, Col: j}, p, true
+			}
+		}
+	}
+
+	if !found {
+		return board.Position{}, errors.New("no hidden cells left")
+	}
+
+	return best, nil
+}
+
+// countOpened returns the number of open cells and the number of cells free from black holes.
+func countOpened(g *game.Game) (opened int, free int) {
+	for _, row := range g.BoardState() {
+		for _, v := range row {
+			if _, ok := v.Clue(); ok {
+				opened++
+			}
+		}
+	}
+
+	cfg := g.Config()
+
+	return opened, cfg.NumRows*cfg.NumCols - g.NumBlackHoles()
+}
@@ -0,0 +1,189 @@
+// Package simulation estimates how winnable games are by letting a solver-based bot play many of them.
+//
+// Game i (counting from 0) of a simulation is created with the seed Seed+i and the bot plays every game the same way,
+// so the results depend only on the configuration, no matter how many workers play the games.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"proxx/internal/proxx/game"
+	"sync"
+)
+
+// HistogramBuckets is the number of the buckets of the progress histogram.
+const HistogramBuckets = 10
+
+// z95 is the quantile of the standard normal distribution for the 95% confidence interval.
+const z95 = 1.959963984540054
+
+// Config represents the configuration of a simulation.
+// GameConfig returns the configuration of the game with the seed.
+type Config struct {
+	Games      int
+	Workers    int
+	Seed       int64
+	GameConfig func(seed int64) (game.Config, error)
+}
+
+// Result represents the games played by the bot in the order of their seeds.
+type Result struct {
+	Games []GameResult
+}
+
+// Run plays the games on a pool of workers. Returns the error of the first game that failed
+// or the error of the context if it's done before all the games are played.
+func Run(ctx context.Context, cfg Config) (Result, error) {
+	if cfg.Games <= 0 {
+		return Result{}, errors.New("the number of games should be positive")
+	}
+
+	if cfg.Workers <= 0 {
+		return Result{}, errors.New("the number of workers should be positive")
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	games := make([]GameResult, cfg.Games)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				r, err := playGame(cfg, cfg.Seed+int64(i))
+				if err != nil {
+					cancel(err)
+					return
+				}
+
+				games[i] = r
+			}
+		}()
+	}
+
+	func() {
+		defer close(jobs)
+
+		for i := 0; i < cfg.Games; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return Result{}, err
+	}
+
+	return Result{Games: games}, nil
+}
+
+func playGame(cfg Config, seed int64) (GameResult, error) {
+	gameCfg, err := cfg.GameConfig(seed)
+	if err != nil {
+		return GameResult{}, err
+	}
+
+	g, err := game.NewGame(gameCfg)
+	if err != nil {
+		return GameResult{}, err
+	}
+
+	r, err := Play(g)
+	r.Seed = seed
+
+	return r, err
+}
+
+// Wins returns the number of the games won.
+func (r Result) Wins() int {
+	n := 0
+
+	for _, g := range r.Games {
+		if g.Won {
+			n++
+		}
+	}
+
+	return n
+}
+
+// WinRate returns the share of the games won, from 0 to 1.
+func (r Result) WinRate() float64 {
+	if len(r.Games) == 0 {
+		return 0
+	}
+
+	return float64(r.Wins()) / float64(len(r.Games))
+}
+
+// WinRateInterval returns the 95% confidence interval of the win rate (the Wilson score interval).
+func (r Result) WinRateInterval() (low float64, high float64) {
+	n := float64(len(r.Games))
+	if n == 0 {
+		return 0, 1
+	}
+
+	p := r.WinRate()
+	z2 := z95 * z95
+
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z95 / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// ProgressHistogram returns the number of the games by their progress: bucket i counts the games that opened
+// from i*10% to (i+1)*10% of the free cells, the last one includes the games won.
+func (r Result) ProgressHistogram() [HistogramBuckets]int {
+	var h [HistogramBuckets]int
+
+	for _, g := range r.Games {
+		// the integer arithmetic keeps the boundaries exact
+		bucket := min(g.Opened*HistogramBuckets/max(g.Free, 1), HistogramBuckets-1)
+		h[bucket]++
+	}
+
+	return h
+}
+
+// Guesses returns the total number of forced guesses.
+func (r Result) Guesses() int {
+	n := 0
+
+	for _, g := range r.Games {
+		n += g.Guesses
+	}
+
+	return n
+}
+
+// GuessCounts returns the number of the games by the number of forced guesses in them, up to maxGuesses:
+// the last element counts the games with maxGuesses guesses or more. wins counts the games won the same way.
+func (r Result) GuessCounts(maxGuesses int) (games []int, wins []int) {
+	games = make([]int, maxGuesses+1)
+	wins = make([]int, maxGuesses+1)
+
+	for _, g := range r.Games {
+		i := min(g.Guesses, maxGuesses)
+
+		games[i]++
+		if g.Won {
+			wins[i]++
+		}
+	}
+
+	return games, wins
+}
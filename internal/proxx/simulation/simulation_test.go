@@ -0,0 +1,124 @@
+package simulation_test
+
+import (
+	"context"
+	"errors"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/simulation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func beginner(seed int64) (game.Config, error) {
+	return game.Config{NumRows: 9, NumCols: 9, NumBlackHoles: 10, FirstClickSafe: true,
+		BlackHoleLocator: game.NewSeededUniformBlackHoleLocator(seed)}, nil
+}
+
+func TestPlay(t *testing.T) {
+	t.Parallel()
+
+	// the first move opens the corner, the rest is proved from the clues
+	g, err := game.NewGame(game.Config{NumRows: 3, NumCols: 3, NumBlackHoles: 1,
+		BlackHoleLocator: game.NewFixedBlackHoleLocator([]board.Position{{Row: 2, Col: 2}})})
+	require.NoError(t, err)
+
+	r, err := simulation.Play(g)
+	require.NoError(t, err)
+
+	assert.True(t, r.Won)
+	assert.Zero(t, r.Guesses)
+	assert.Equal(t, 8, r.Opened)
+	assert.Equal(t, 8, r.Free)
+	assert.Equal(t, 1.0, r.Progress())
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	cfg := simulation.Config{Games: 60, Workers: 1, Seed: 7, GameConfig: beginner}
+
+	single, err := simulation.Run(context.Background(), cfg)
+	require.NoError(t, err)
+
+	cfg.Workers = 4
+
+	pool, err := simulation.Run(context.Background(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, single, pool, "the results shouldn't depend on the number of workers")
+
+	require.Len(t, pool.Games, 60)
+
+	for i, g := range pool.Games {
+		assert.Equal(t, int64(7+i), g.Seed)
+	}
+
+	assert.Positive(t, pool.Wins(), "the bot should win some beginner games")
+
+	low, high := pool.WinRateInterval()
+	assert.Less(t, low, pool.WinRate())
+	assert.Greater(t, high, pool.WinRate())
+
+	h := pool.ProgressHistogram()
+	total := 0
+	for _, n := range h {
+		total += n
+	}
+
+	assert.Equal(t, 60, total)
+	assert.GreaterOrEqual(t, h[simulation.HistogramBuckets-1], pool.Wins())
+
+	games, wins := pool.GuessCounts(3)
+	require.Len(t, games, 4)
+	assert.Equal(t, 60, games[0]+games[1]+games[2]+games[3])
+	assert.Equal(t, pool.Wins(), wins[0]+wins[1]+wins[2]+wins[3])
+
+	guesses := 0
+	for _, g := range pool.Games {
+		guesses += g.Guesses
+	}
+
+	assert.Equal(t, guesses, pool.Guesses())
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := simulation.Run(context.Background(), simulation.Config{Games: 0, Workers: 1, GameConfig: beginner})
+	assert.Error(t, err)
+
+	_, err = simulation.Run(context.Background(), simulation.Config{Games: 1, Workers: 0, GameConfig: beginner})
+	assert.Error(t, err)
+
+	errConfig := errors.New("no config")
+
+	_, err = simulation.Run(context.Background(), simulation.Config{Games: 10, Workers: 3,
+		GameConfig: func(int64) (game.Config, error) { return game.Config{}, errConfig }})
+	assert.ErrorIs(t, err, errConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = simulation.Run(ctx, simulation.Config{Games: 1000, Workers: 2, GameConfig: beginner})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestResult_WinRateInterval(t *testing.T) {
+	t.Parallel()
+
+	r := simulation.Result{Games: make([]simulation.GameResult, 100)}
+	for i := 0; i < 40; i++ {
+		r.Games[i].Won = true
+	}
+
+	low, high := r.WinRateInterval()
+	assert.InDelta(t, 0.3094, low, 1e-4)
+	assert.InDelta(t, 0.4980, high, 1e-4)
+
+	low, high = simulation.Result{Games: make([]simulation.GameResult, 10)}.WinRateInterval()
+	assert.Zero(t, low)
+	assert.InDelta(t, 0.2775, high, 1e-4)
+}
@@ -0,0 +1,70 @@
+package solver
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+)
+
+// Game is the interface that wraps the methods the solver needs to play a game.
+// Only the public state of the board is used. R is the type of the result of opening a cell,
+// the solver ignores it, e.g. game.Game is played via Play[game.OpenResult].
+type Game[R any] interface {
+	BoardState() [][]board.CellValue
+	NumBlackHoles() int
+	OpenCell(row int, col int) (R, error)
+	IsOver() bool
+}
+
+// Report represents the result of playing a game by the solver.
+// Moves contains every move the solver made together with the deduced black holes.
+// Stuck is true if the game isn't over but no move can be proved anymore.
+type Report struct {
+	Moves []Move
+	Stuck bool
+}
+
+// Play opens every cell that can be proved safe until the game is over or the solver gets stuck.
+// Flagged cells are never opened, so a wrong flag can leave the solver stuck.
+func Play[R any](g Game[R]) (Report, error) {
+	var (
+		report   Report
+		reported = make(map[board.Position]bool)
+	)
+
+	for !g.IsOver() {
+		opened := false
+
+		for _, m := range Solve(g.BoardState(), g.NumBlackHoles()) {
+			if m.BlackHole {
+				if !reported[m.Position] {
+					reported[m.Position] = true
+					report.Moves = append(report.Moves, m)
+				}
+				continue
+			}
+
+			// flags are left to the player, even the ones put on cells proved safe
+			if g.BoardState()[m.Position.Row][m.Position.Col] != board.CellValueUnknown {
+				continue
+			}
+
+			if _, err := g.OpenCell(m.Position.Row, m.Position.Col); err != nil {
+				return report, fmt.Errorf("failed to open the cell at %v: %w", m.Position, err)
+			}
+
+			report.Moves = append(report.Moves, m)
+			opened = true
+
+			if g.IsOver() {
+				break
+			}
+		}
+
+		if !opened {
+			report.Stuck = !g.IsOver()
+			break
+		}
+	}
+
+	return report, nil
+}
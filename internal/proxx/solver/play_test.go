@@ -0,0 +1,90 @@
+package solver_test
+
+import (
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/game"
+	"proxx/internal/proxx/solver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type predefinedBlackHoleLocator struct {
+	positions []board.Position
+}
+
+func (p predefinedBlackHoleLocator) LocateBlackHolesOnBoard(_ int, _ int, _ int) []board.Position {
+	return p.positions
+}
+
+func TestPlay(t *testing.T) {
+	gameCfg := game.Config{
+		NumRows:          3,
+		NumCols:          3,
+		NumBlackHoles:    2,
+		BlackHoleLocator: predefinedBlackHoleLocator{positions: []board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}},
+	}
+
+	t.Run("Stuck without clues", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		report, err := solver.Play[game.OpenResult](g)
+		require.NoError(t, err)
+
+		assert.True(t, report.Stuck)
+		assert.Empty(t, report.Moves)
+		assert.False(t, g.IsOver())
+	})
+
+	t.Run("Win after the first move", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		_, err = g.OpenCell(2, 0)
+		require.NoError(t, err)
+
+		report, err := solver.Play[game.OpenResult](g)
+		require.NoError(t, err)
+
+		assert.False(t, report.Stuck)
+		assert.True(t, g.IsWon())
+
+		require.Len(t, report.Moves, 3)
+		assert.True(t, report.Moves[0].BlackHole)
+		assert.True(t, report.Moves[1].BlackHole)
+		assert.EqualValues(t, board.Position{Row: 0, Col: 2}, report.Moves[2].Position)
+		assert.False(t, report.Moves[2].BlackHole)
+		assert.EqualValues(t, solver.RuleSingleClue, report.Moves[2].Rule)
+		assert.EqualValues(t, []board.Position{{Row: 0, Col: 1}, {Row: 1, Col: 2}}, report.Moves[2].Reason.Positions())
+	})
+
+	t.Run("Wrong flag", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := game.NewGame(gameCfg)
+		require.NoError(t, err)
+
+		// the cell is free from black holes, the solver proves it but leaves the flag alone
+		require.NoError(t, g.ToggleFlag(0, 2))
+
+		_, err = g.OpenCell(2, 0)
+		require.NoError(t, err)
+
+		report, err := solver.Play[game.OpenResult](g)
+		require.NoError(t, err)
+
+		assert.True(t, report.Stuck)
+		assert.False(t, g.IsOver())
+		assert.EqualValues(t, board.CellValueFlag, g.BoardState()[0][2])
+
+		for _, m := range report.Moves {
+			assert.True(t, m.BlackHole)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package solver
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"strings"
+)
+
+// Reason explains why a move was made. The positions it refers to stay structured,
+// so every front end writes them in its own notation.
+type Reason struct {
+	format string
+	args   []any
+}
+
+// NewReason returns the reason described by the format in the style of fmt.Sprintf.
+// Arguments of type board.Position and []board.Position are written by Explain.
+func NewReason(format string, args ...any) Reason {
+	return Reason{format: format, args: args}
+}
+
+// Explain returns the reason with every position written by formatPosition and put in brackets.
+func (r Reason) Explain(formatPosition func(board.Position) string) string {
+	args := make([]any, len(r.args))
+
+	for i, arg := range r.args {
+		switch arg := arg.(type) {
+		case board.Position:
+			args[i] = "(" + formatPosition(arg) + ")"
+		case []board.Position:
+			formatted := make([]string, 0, len(arg))
+			for _, p := range arg {
+				formatted = append(formatted, "("+formatPosition(p)+")")
+			}
+
+			args[i] = strings.Join(formatted, ", ")
+		default:
+			args[i] = arg
+		}
+	}
+
+	return fmt.Sprintf(r.format, args...)
+}
+
+// Positions returns the positions the reason refers to in the order they are mentioned.
+func (r Reason) Positions() []board.Position {
+	var positions []board.Position
+
+	for _, arg := range r.args {
+		switch arg := arg.(type) {
+		case board.Position:
+			positions = append(positions, arg)
+		case []board.Position:
+			positions = append(positions, arg...)
+		}
+	}
+
+	return positions
+}
@@ -0,0 +1,334 @@
+// Package solver deduces moves using only the public state of a game board.
+package solver
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+)
+
+// Rule represents a kind of reasoning that proved a move.
+type Rule int
+
+const (
+	// RuleSingleClue means that a clue alone decides all its unknown neighbors.
+	RuleSingleClue Rule = iota
+	// RuleSubset means that the unknown neighbors of one clue are a subset of the unknown neighbors of another clue.
+	RuleSubset
+	// RuleBlackHoleCount means that the total number of black holes decides the cells.
+	RuleBlackHoleCount
+)
+
+func (r Rule) String() string {
+	switch r {
+	case RuleSingleClue:
+		return "single clue"
+	case RuleSubset:
+		return "subset"
+	case RuleBlackHoleCount:
+		return "black hole count"
+	default:
+		return "unknown rule"
+	}
+}
+
+// Move represents a deduced move: the cell at Position is either safe to open
+// or is a black hole (BlackHole is true). Reason explains why the move was made.
+type Move struct {
+	Position  board.Position
+	BlackHole bool
+	Rule      Rule
+	Reason    Reason
+}
+
+// Solve returns all the moves that can be proved from the board state.
+// The state is the one returned by board.Board.State(): only opened cells are known.
+// totalBlackHoles is the number of black holes on the whole board.
+// Moves are returned in the order they were deduced, later moves may rely on the earlier ones.
+func Solve(state [][]board.CellValue, totalBlackHoles int) []Move {
+	s := newSolver(state, totalBlackHoles)
+
+	for progress := true; progress; {
+		progress = s.singleClue() || s.subset() || s.blackHoleCount()
+	}
+
+	return s.moves
+}
+
+// solver keeps cells deduced so far: true means a black hole, false - a safe cell.
+type solver struct {
+	state           [][]board.CellValue
+	totalBlackHoles int
+	known           map[board.Position]bool
+	moves           []Move
+}
+
+func newSolver(state [][]board.CellValue, totalBlackHoles int) *solver {
+	return &solver{state: state, totalBlackHoles: totalBlackHoles, known: make(map[board.Position]bool)}
+}
+
+// clue describes a revealed clue together with its still undecided neighbors.
+type clue struct {
+	pos       board.Position
+	value     int
+	unknown   []board.Position
+	holes     []board.Position
+	remaining int
+}
+
+// singleClue decides the neighbors of a clue that is either already satisfied
+// or needs all its undecided neighbors to be black holes.
+func (s *solver) singleClue() bool {
+	for _, c := range s.clues() {
+		if c.remaining == 0 {
+			s.decide(c.unknown, false, RuleSingleClue, satisfiedBy(c.value, c.pos, c.holes))
+			return true
+		}
+
+		if c.remaining == len(c.unknown) {
+			s.decide(c.unknown, true, RuleSingleClue,
+				NewReason("the %d at %s needs all its %d unopened neighbors to be black holes",
+					c.value, c.pos, len(c.unknown)))
+			return true
+		}
+	}
+
+	return false
+}
+
+// subset compares clues whose undecided neighbors contain each other.
+// If the smaller clue needs as many black holes as the bigger one, the rest of the bigger clue is safe.
+// If the difference in needed black holes equals the number of the rest cells, they all are black holes.
+func (s *solver) subset() bool {
+	clues := s.clues()
+
+	for _, a := range clues {
+		for _, b := range clues {
+			if a.pos == b.pos || !isSubset(a.unknown, b.unknown) {
+				continue
+			}
+
+			rest := difference(b.unknown, a.unknown)
+			if len(rest) == 0 {
+				continue
+			}
+
+			if b.remaining == a.remaining {
+				s.decide(rest, false, RuleSubset,
+					NewReason("the %d at %s and the %d at %s share the same %s, "+
+						"so the other neighbors of %s are safe", a.value, a.pos,
+						b.value, b.pos, pluralBlackHoles(a.remaining), b.pos))
+				return true
+			}
+
+			if b.remaining-a.remaining == len(rest) {
+				s.decide(rest, true, RuleSubset,
+					NewReason("the %d at %s can take only %s of the %d needed by the %d at %s, "+
+						"so its other neighbors are black holes", a.value, a.pos,
+						pluralBlackHoles(a.remaining), b.remaining, b.value, b.pos))
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// blackHoleCount uses the total number of black holes.
+// If no black holes are left, every undecided cell is safe.
+// If the undecided cells are as many as the black holes left, all of them are black holes.
+// If clues that don't share cells need all the black holes left, every other undecided cell is safe.
+func (s *solver) blackHoleCount() bool {
+	undecided, remaining := s.undecided()
+	if len(undecided) == 0 {
+		return false
+	}
+
+	if remaining == 0 {
+		s.decide(undecided, false, RuleBlackHoleCount, NewReason("all the black holes are already found"))
+		return true
+	}
+
+	if remaining == len(undecided) {
+		s.decide(undecided, true, RuleBlackHoleCount,
+			NewReason("%s left for the same number of unopened cells", pluralBlackHoles(remaining)))
+		return true
+	}
+
+	var (
+		covered  []board.Position
+		disjoint []board.Position
+		needed   int
+	)
+
+	for _, c := range s.clues() {
+		if intersects(covered, c.unknown) {
+			continue
+		}
+
+		covered = append(covered, c.unknown...)
+		disjoint = append(disjoint, c.pos)
+		needed += c.remaining
+	}
+
+	if needed != remaining {
+		return false
+	}
+
+	rest := difference(undecided, covered)
+	if len(rest) == 0 {
+		return false
+	}
+
+	s.decide(rest, false, RuleBlackHoleCount,
+		NewReason("the clues at %s need all the %s left", disjoint, pluralBlackHoles(remaining)))
+
+	return true
+}
+
+func (s *solver) decide(positions []board.Position, isBlackHole bool, rule Rule, reason Reason) {
+	for _, p := range positions {
+		s.known[p] = isBlackHole
+		s.moves = append(s.moves, Move{Position: p, BlackHole: isBlackHole, Rule: rule, Reason: reason})
+	}
+}
+
+// clues collects all the revealed clues that still have undecided neighbors.
+func (s *solver) clues() []clue {
+	var clues []clue
+
+	for i, row := range s.state {
+		for j, v := range row {
+			value, ok := v.Clue()
+			if !ok {
+				continue
+			}
+
+			c := clue{pos: board.Position{Row: i, Col: j}, value: value}
+
+			for _, p := range board.SurroundingPositions(len(s.state), len(row), i, j) {
+				nv := s.state[p.Row][p.Col]
+
+				if nv == board.CellValueBlackHole {
+					c.holes = append(c.holes, p)
+					continue
+				}
+
+				if !nv.IsHidden() {
+					continue
+				}
+
+				if isHole, decided := s.known[p]; decided {
+					if isHole {
+						c.holes = append(c.holes, p)
+					}
+					continue
+				}
+
+				c.unknown = append(c.unknown, p)
+			}
+
+			c.remaining = c.value - len(c.holes)
+
+			if len(c.unknown) > 0 {
+				clues = append(clues, c)
+			}
+		}
+	}
+
+	return clues
+}
+
+// undecided returns the unknown cells that aren't deduced yet and the number of black holes left among them.
+func (s *solver) undecided() ([]board.Position, int) {
+	var undecided []board.Position
+
+	remaining := s.totalBlackHoles
+
+	for i, row := range s.state {
+		for j, v := range row {
+			pos := board.Position{Row: i, Col: j}
+
+			if v == board.CellValueBlackHole {
+				remaining--
+				continue
+			}
+
+			if !v.IsHidden() {
+				continue
+			}
+
+			if isHole, decided := s.known[pos]; decided {
+				if isHole {
+					remaining--
+				}
+				continue
+			}
+
+			undecided = append(undecided, pos)
+		}
+	}
+
+	return undecided, remaining
+}
+
+func isSubset(sub []board.Position, set []board.Position) bool {
+	for _, p := range sub {
+		if !contains(set, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func intersects(a []board.Position, b []board.Position) bool {
+	for _, p := range b {
+		if contains(a, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func difference(set []board.Position, sub []board.Position) []board.Position {
+	var result []board.Position
+
+	for _, p := range set {
+		if !contains(sub, p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func contains(positions []board.Position, pos board.Position) bool {
+	for _, p := range positions {
+		if p == pos {
+			return true
+		}
+	}
+
+	return false
+}
+
+// satisfiedBy explains why a clue doesn't need more black holes.
+func satisfiedBy(value int, pos board.Position, holes []board.Position) Reason {
+	switch len(holes) {
+	case 0:
+		return NewReason("the %d at %s has no black holes around it", value, pos)
+	case 1:
+		return NewReason("the %d at %s is already satisfied by the black hole at %s", value, pos, holes[0])
+	default:
+		return NewReason("the %d at %s is already satisfied by the black holes at %s", value, pos, holes)
+	}
+}
+
+func pluralBlackHoles(n int) string {
+	if n == 1 {
+		return "1 black hole"
+	}
+
+	return fmt.Sprintf("%d black holes", n)
+}
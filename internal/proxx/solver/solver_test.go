@@ -0,0 +1,136 @@
+package solver_test
+
+import (
+	"fmt"
+	"proxx/internal/proxx/board"
+	"proxx/internal/proxx/solver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// explainedMove is a move with its reason written in the one-based notation.
+type explainedMove struct {
+	Position  board.Position
+	BlackHole bool
+	Rule      solver.Rule
+	Reason    string
+}
+
+func oneBased(p board.Position) string {
+	return fmt.Sprintf("%d,%d", p.Row+1, p.Col+1)
+}
+
+func explain(moves []solver.Move) []explainedMove {
+	var explained []explainedMove
+
+	for _, m := range moves {
+		explained = append(explained, explainedMove{Position: m.Position, BlackHole: m.BlackHole, Rule: m.Rule,
+			Reason: m.Reason.Explain(oneBased)})
+	}
+
+	return explained
+}
+
+func TestSolve(t *testing.T) {
+	testCases := []struct {
+		name            string
+		state           [][]board.CellValue
+		totalBlackHoles int
+		expected        []explainedMove
+	}{
+		{
+			name:            "Nothing is known",
+			state:           [][]board.CellValue{{"?", "?"}, {"?", "?"}},
+			totalBlackHoles: 1,
+			expected:        nil,
+		},
+		{
+			name: "Single clue rules",
+			state: [][]board.CellValue{
+				{"0", "1", "?"},
+				{"0", "2", "?"},
+				{"0", "2", "?"},
+			},
+			totalBlackHoles: 2,
+			expected: []explainedMove{
+				{Position: board.Position{Row: 1, Col: 2}, BlackHole: true, Rule: solver.RuleSingleClue,
+					Reason: "the 2 at (3,2) needs all its 2 unopened neighbors to be black holes"},
+				{Position: board.Position{Row: 2, Col: 2}, BlackHole: true, Rule: solver.RuleSingleClue,
+					Reason: "the 2 at (3,2) needs all its 2 unopened neighbors to be black holes"},
+				{Position: board.Position{Row: 0, Col: 2}, BlackHole: false, Rule: solver.RuleSingleClue,
+					Reason: "the 1 at (1,2) is already satisfied by the black hole at (2,3)"},
+			},
+		},
+		{
+			name: "Subset rule",
+			state: [][]board.CellValue{
+				{"?", "?", "?"},
+				{"1", "2", "1"},
+			},
+			totalBlackHoles: 2,
+			expected: []explainedMove{
+				{Position: board.Position{Row: 0, Col: 2}, BlackHole: true, Rule: solver.RuleSubset,
+					Reason: "the 1 at (2,1) can take only 1 black hole of the 2 needed by the 2 at (2,2), " +
+						"so its other neighbors are black holes"},
+				{Position: board.Position{Row: 0, Col: 1}, BlackHole: false, Rule: solver.RuleSingleClue,
+					Reason: "the 1 at (2,3) is already satisfied by the black hole at (1,3)"},
+				{Position: board.Position{Row: 0, Col: 0}, BlackHole: true, Rule: solver.RuleSingleClue,
+					Reason: "the 1 at (2,1) needs all its 1 unopened neighbors to be black holes"},
+			},
+		},
+		{
+			name:            "Clues need all the black holes left",
+			state:           [][]board.CellValue{{"?", "1", "?", "?", "?"}},
+			totalBlackHoles: 1,
+			expected: []explainedMove{
+				{Position: board.Position{Row: 0, Col: 3}, BlackHole: false, Rule: solver.RuleBlackHoleCount,
+					Reason: "the clues at (1,2) need all the 1 black hole left"},
+				{Position: board.Position{Row: 0, Col: 4}, BlackHole: false, Rule: solver.RuleBlackHoleCount,
+					Reason: "the clues at (1,2) need all the 1 black hole left"},
+			},
+		},
+		{
+			name:            "As many cells as black holes left",
+			state:           [][]board.CellValue{{"?", "?"}},
+			totalBlackHoles: 2,
+			expected: []explainedMove{
+				{Position: board.Position{Row: 0, Col: 0}, BlackHole: true, Rule: solver.RuleBlackHoleCount,
+					Reason: "2 black holes left for the same number of unopened cells"},
+				{Position: board.Position{Row: 0, Col: 1}, BlackHole: true, Rule: solver.RuleBlackHoleCount,
+					Reason: "2 black holes left for the same number of unopened cells"},
+			},
+		},
+		{
+			name:            "No black holes left",
+			state:           [][]board.CellValue{{"?", "?"}},
+			totalBlackHoles: 0,
+			expected: []explainedMove{
+				{Position: board.Position{Row: 0, Col: 0}, BlackHole: false, Rule: solver.RuleBlackHoleCount,
+					Reason: "all the black holes are already found"},
+				{Position: board.Position{Row: 0, Col: 1}, BlackHole: false, Rule: solver.RuleBlackHoleCount,
+					Reason: "all the black holes are already found"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualValues(t, tc.expected, explain(solver.Solve(tc.state, tc.totalBlackHoles)))
+		})
+	}
+}
+
+func TestReason(t *testing.T) {
+	holes := []board.Position{{Row: 1, Col: 2}, {Row: 2, Col: 2}}
+	r := solver.NewReason("the %d at %s is already satisfied by the black holes at %s",
+		2, board.Position{Row: 2, Col: 1}, holes)
+
+	assert.Equal(t, "the 2 at (3,2) is already satisfied by the black holes at (2,3), (3,3)", r.Explain(oneBased))
+	assert.Equal(t, "the 2 at (2,1) is already satisfied by the black holes at (1,2), (2,2)",
+		r.Explain(func(p board.Position) string { return fmt.Sprintf("%d,%d", p.Row, p.Col) }))
+	assert.Equal(t, []board.Position{{Row: 2, Col: 1}, {Row: 1, Col: 2}, {Row: 2, Col: 2}}, r.Positions())
+}
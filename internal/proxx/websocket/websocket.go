@@ -0,0 +1,372 @@
+// Package websocket implements the WebSocket protocol (RFC 6455) on top of net/http:
+// the server side handshake, a client for tools and tests and the framing of messages.
+// Extensions and subprotocols aren't supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// acceptGUID is appended to the client's key to compute the accept key of the handshake.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	// DefaultMaxMessageSize limits the size of a received message.
+	DefaultMaxMessageSize = 1 << 20
+	// maxCloseReason is the longest reason that fits into a close frame with a status code.
+	maxCloseReason = 123
+)
+
+// Opcodes of frames.
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// Status codes of closing a connection.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseNoStatus        = 1005
+	CloseInvalidData     = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+)
+
+var (
+	ErrBadHandshake   = errors.New("bad websocket handshake")
+	ErrProtocol       = errors.New("websocket protocol violation")
+	ErrMessageTooBig  = errors.New("websocket message is too big")
+	ErrInvalidUTF8    = errors.New("websocket text message isn't valid UTF-8")
+	ErrConnectionDone = errors.New("websocket connection is closed")
+)
+
+// CloseError is returned by ReadMessage when the peer closes the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+}
+
+// Conn represents a WebSocket connection. Messages may be written by one goroutine and read by another one,
+// writes are safe for concurrent use.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+	// MaxMessageSize limits the size of a received message.
+	MaxMessageSize int
+
+	writeMu   sync.Mutex
+	closeSent bool
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{conn: conn, br: br, client: client, MaxMessageSize: DefaultMaxMessageSize}
+}
+
+// acceptKey returns the value of the Sec-WebSocket-Accept header for the client's key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains checks whether the comma-separated header value contains the token, case-insensitively.
+func headerContains(value string, token string) bool {
+	for _, v := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetReadDeadline sets the deadline of reading messages.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline of writing messages.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// WriteText writes the text message.
+func (c *Conn) WriteText(data []byte) error {
+	return c.WriteMessage(OpText, data)
+}
+
+// WriteMessage writes the message as a single frame.
+func (c *Conn) WriteMessage(opcode int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return ErrConnectionDone
+	}
+
+	if opcode == OpClose {
+		c.closeSent = true
+	}
+
+	return c.writeFrame(opcode, data)
+}
+
+// writeFrame writes a final frame, frames written by a client are masked.
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | byte(opcode)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = maskBit | byte(n)
+	case n <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = maskBit | 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+
+		header = append(header, mask[:]...)
+
+		masked := make([]byte, len(payload))
+		copy(masked, payload)
+		maskBytes(mask, masked)
+		payload = masked
+	}
+
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
+
+// frame represents a received frame.
+type frame struct {
+	fin     bool
+	opcode  int
+	payload []byte
+}
+
+// readFrame reads a frame and checks it follows the protocol: frames of a client must be masked,
+// frames of a server mustn't be, control frames are short and never fragmented.
+func (c *Conn) readFrame() (frame, error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return frame{}, err
+	}
+
+	f := frame{fin: header[0]&0x80 != 0, opcode: int(header[0] & 0x0F)}
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 {
+		return frame{}, fmt.Errorf("%w: reserved bits are set", ErrProtocol)
+	}
+
+	if masked == c.client {
+		return frame{}, fmt.Errorf("%w: wrong masking of a frame", ErrProtocol)
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return frame{}, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return frame{}, err
+		}
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if f.opcode >= OpClose && (length > 125 || !f.fin) {
+		return frame{}, fmt.Errorf("%w: invalid control frame", ErrProtocol)
+	}
+
+	if length > uint64(c.MaxMessageSize) {
+		return frame{}, ErrMessageTooBig
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return frame{}, err
+		}
+	}
+
+	f.payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, f.payload); err != nil {
+		return frame{}, err
+	}
+
+	if masked {
+		maskBytes(mask, f.payload)
+	}
+
+	return f, nil
+}
+
+// ReadMessage reads the next text or binary message, fragmented messages are joined.
+// Pings are answered, pongs are skipped. When the peer closes the connection, the close frame is echoed
+// and a *CloseError is returned. A violation of the protocol closes the connection with the matching code.
+func (c *Conn) ReadMessage() (opcode int, data []byte, err error) {
+	var message []byte
+
+	opcode = -1
+
+	for {
+		f, err := c.readFrame()
+		if err != nil {
+			c.failOnError(err)
+			return 0, nil, err
+		}
+
+		switch f.opcode {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, f.payload); err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			return 0, nil, c.handleClose(f.payload)
+		case OpText, OpBinary:
+			if opcode != -1 {
+				err := fmt.Errorf("%w: a new message in the middle of a fragmented one", ErrProtocol)
+				c.failOnError(err)
+
+				return 0, nil, err
+			}
+
+			opcode = f.opcode
+		case OpContinuation:
+			if opcode == -1 {
+				err := fmt.Errorf("%w: continuation without a message", ErrProtocol)
+				c.failOnError(err)
+
+				return 0, nil, err
+			}
+		default:
+			err := fmt.Errorf("%w: unknown opcode %d", ErrProtocol, f.opcode)
+			c.failOnError(err)
+
+			return 0, nil, err
+		}
+
+		if len(message)+len(f.payload) > c.MaxMessageSize {
+			c.failOnError(ErrMessageTooBig)
+			return 0, nil, ErrMessageTooBig
+		}
+
+		message = append(message, f.payload...)
+
+		if !f.fin {
+			continue
+		}
+
+		if opcode == OpText && !utf8.Valid(message) {
+			c.failOnError(ErrInvalidUTF8)
+			return 0, nil, ErrInvalidUTF8
+		}
+
+		return opcode, message, nil
+	}
+}
+
+// handleClose echoes the close frame of the peer and returns it as an error.
+func (c *Conn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatus}
+
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+
+	echo := payload
+	if len(payload) >= 2 {
+		echo = payload[:2]
+	}
+
+	_ = c.WriteMessage(OpClose, echo)
+
+	return closeErr
+}
+
+// failOnError closes the connection with the code matching the error of the protocol.
+func (c *Conn) failOnError(err error) {
+	switch {
+	case errors.Is(err, ErrProtocol):
+		_ = c.Close(CloseProtocolError, "")
+	case errors.Is(err, ErrMessageTooBig):
+		_ = c.Close(CloseMessageTooBig, "")
+	case errors.Is(err, ErrInvalidUTF8):
+		_ = c.Close(CloseInvalidData, "")
+	}
+}
+
+// Close sends the close frame with the code and the reason unless it was sent already and closes the connection.
+// The reason is cut to fit into a control frame.
+func (c *Conn) Close(code int, reason string) error {
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+	}
+
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+
+	_ = c.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = c.WriteMessage(OpClose, payload)
+
+	return c.conn.Close()
+}
@@ -0,0 +1,254 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// the example of RFC 6455, section 1.3
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+// newEchoServer starts a server that echoes messages until the client closes the connection.
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+
+		for {
+			op, data, err := conn.ReadMessage()
+			if err != nil {
+				conn.Close(CloseNormal, "")
+				return
+			}
+
+			if err := conn.WriteMessage(op, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func TestDial(t *testing.T) {
+	url := newEchoServer(t)
+
+	conn, err := Dial(context.Background(), url)
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 5, 125, 126, 65535, 65536, 100000} {
+		payload := bytes.Repeat([]byte("a"), size)
+
+		require.NoError(t, conn.WriteText(payload))
+
+		op, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, OpText, op)
+		assert.Equal(t, string(payload), string(data), "message of %d bytes", size)
+	}
+
+	require.NoError(t, conn.WriteMessage(OpBinary, []byte{0, 1, 2}))
+
+	op, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, OpBinary, op)
+	assert.Equal(t, []byte{0, 1, 2}, data)
+
+	// the server echoes the close frame
+	require.NoError(t, conn.WriteMessage(OpClose, binary.BigEndian.AppendUint16(nil, CloseNormal)))
+
+	_, _, err = conn.ReadMessage()
+
+	var closeErr *CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, CloseNormal, closeErr.Code)
+}
+
+func TestUpgrade_BadHandshake(t *testing.T) {
+	url := newEchoServer(t)
+
+	resp, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, "http"+strings.TrimPrefix(url, "ws"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+	assert.Equal(t, "13", resp.Header.Get("Sec-WebSocket-Version"))
+
+	_, err = Dial(context.Background(), "wss://example.com")
+	assert.ErrorIs(t, err, ErrBadHandshake)
+}
+
+// newPipe returns the server and the client ends of a connection, the client frames are written raw.
+func newPipe(t *testing.T) (*Conn, net.Conn) {
+	t.Helper()
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+
+	return newConn(server, bufio.NewReader(server), false), client
+}
+
+// rawFrame builds a masked client frame.
+func rawFrame(fin bool, opcode int, payload []byte) []byte {
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+
+	mask := [4]byte{1, 2, 3, 4}
+	masked := append([]byte(nil), payload...)
+	maskBytes(mask, masked)
+
+	frame := []byte{first, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+
+	return append(frame, masked...)
+}
+
+// readServerFrame reads a frame written by the server to the client's end.
+func readServerFrame(t *testing.T, client net.Conn) frame {
+	t.Helper()
+
+	c := newConn(client, bufio.NewReader(client), true)
+
+	f, err := c.readFrame()
+	require.NoError(t, err)
+
+	return f
+}
+
+func TestConn_ReadMessage(t *testing.T) {
+	t.Run("Fragmented message with a ping in the middle", func(t *testing.T) {
+		t.Parallel()
+
+		server, client := newPipe(t)
+
+		go func() {
+			client.Write(rawFrame(false, OpText, []byte("hel")))
+			client.Write(rawFrame(true, OpPing, []byte("ping")))
+		}()
+
+		result := make(chan string, 1)
+
+		go func() {
+			_, data, err := server.ReadMessage()
+			assert.NoError(t, err)
+			result <- string(data)
+		}()
+
+		pong := readServerFrame(t, client)
+		assert.Equal(t, OpPong, pong.opcode)
+		assert.Equal(t, []byte("ping"), pong.payload)
+
+		go client.Write(rawFrame(true, OpContinuation, []byte("lo")))
+
+		assert.Equal(t, "hello", <-result)
+	})
+
+	testCases := []struct {
+		name  string
+		frame []byte
+		err   error
+		code  int
+	}{
+		{name: "Unmasked frame", frame: []byte{0x81, 0x02, 'h', 'i'}, err: ErrProtocol, code: CloseProtocolError},
+		{name: "Reserved bits", frame: append([]byte{0xC1}, rawFrame(true, OpText, []byte("hi"))[1:]...),
+			err: ErrProtocol, code: CloseProtocolError},
+		{name: "Unknown opcode", frame: rawFrame(true, 0x3, nil), err: ErrProtocol, code: CloseProtocolError},
+		{name: "Continuation without a message", frame: rawFrame(true, OpContinuation, []byte("x")),
+			err: ErrProtocol, code: CloseProtocolError},
+		{name: "Fragmented control frame", frame: rawFrame(false, OpPing, nil), err: ErrProtocol, code: CloseProtocolError},
+		{name: "Invalid UTF-8", frame: rawFrame(true, OpText, []byte{0xff, 0xfe}), err: ErrInvalidUTF8, code: CloseInvalidData},
+		{name: "Message is too big", frame: rawFrame(true, OpText, []byte(strings.Repeat("a", 20))),
+			err: ErrMessageTooBig, code: CloseMessageTooBig},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, client := newPipe(t)
+			server.MaxMessageSize = 10
+
+			go client.Write(tc.frame)
+
+			errs := make(chan error, 1)
+
+			go func() {
+				_, _, err := server.ReadMessage()
+				errs <- err
+			}()
+
+			closing := readServerFrame(t, client)
+			assert.Equal(t, OpClose, closing.opcode)
+			assert.Equal(t, tc.code, int(binary.BigEndian.Uint16(closing.payload)))
+
+			select {
+			case err := <-errs:
+				assert.ErrorIs(t, err, tc.err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("ReadMessage doesn't return")
+			}
+		})
+	}
+
+	t.Run("Close frame is echoed", func(t *testing.T) {
+		t.Parallel()
+
+		server, client := newPipe(t)
+
+		payload := append(binary.BigEndian.AppendUint16(nil, CloseGoingAway), "bye"...)
+		go client.Write(rawFrame(true, OpClose, payload))
+
+		errs := make(chan error, 1)
+
+		go func() {
+			_, _, err := server.ReadMessage()
+			errs <- err
+		}()
+
+		echo := readServerFrame(t, client)
+		assert.Equal(t, OpClose, echo.opcode)
+		assert.Equal(t, CloseGoingAway, int(binary.BigEndian.Uint16(echo.payload)))
+
+		var closeErr *CloseError
+		require.True(t, errors.As(<-errs, &closeErr))
+		assert.Equal(t, &CloseError{Code: CloseGoingAway, Reason: "bye"}, closeErr)
+
+		assert.ErrorIs(t, server.WriteText([]byte("late")), ErrConnectionDone)
+	})
+}